@@ -1,6 +1,9 @@
 package accountingcategories
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 )
@@ -11,18 +14,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -31,11 +35,21 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all accounting categories, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[AccountingCategory, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []AccountingCategory { return r.AccountingCategories }, opts...)
+}
+
 type AllResponse struct {
 	AccountingCategories []AccountingCategory
 	Cursor               string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{
 		Limitation: &json.Limitation{},
@@ -52,8 +66,16 @@ type AllRequest struct {
 	Limitation            *json.Limitation            `json:"Limitation,omitempty"`
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	if r.Limitation == nil {
+		r.Limitation = &json.Limitation{}
+	}
+	return r.Limitation
+}
+
 type AccountingCategory struct {
 	ID                 string `json:"ID"`                 // Unique identifier of the category.
+	EnterpriseID       string `json:"EnterpriseId"`       // Unique identifier of the Enterprise.
 	IsActive           bool   `json:"IsActive"`           // Whether the accounting category is still active.
 	Name               string `json:"Name"`               // Name of the category.
 	Code               string `json:"Code"`               // Code of the category within Mews.
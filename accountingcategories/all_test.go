@@ -1,57 +1,32 @@
-// +build !integration
-
-package accountingcategories
+package accountingcategories_test
 
 import (
-	"net/http"
-	"net/http/httptest"
-	"net/url"
 	"testing"
-	"time"
-)
 
-const (
-	demoBaseURL = "https://demo.mews.li/api/connector/v1/"
-	token       = "C66EF7B239D24632943D115EDE9CB810-EA00F8FD8294692C940F6B5A8F9453D"
+	"github.com/omniboost/go-mews/accountingcategories"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
 )
 
-var (
-	mux     *http.ServeMux
-	client  *Service
-	server  *httptest.Server
-	service *Service
-)
-
-func setup() {
-	mux = http.NewServeMux()
-	server = httptest.NewServer(mux)
-
-	baseURL, _ := url.Parse(demoBaseURL)
-
-	service = NewService()
-	service.Client.BaseURL = baseURL
-	service.Client.AccessToken = token
-
-	// set custom http client
-	service.Client.Client = &http.Client{
-		Timeout: time.Duration(5 * time.Second),
+func TestAll(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+	server.Store.AccountingCategories = []accountingcategories.AccountingCategory{
+		{ID: "1", IsActive: true, Name: "Accommodation", Code: "ACC"},
+		{ID: "2", IsActive: true, Name: "Food", Code: "FB"},
+		{ID: "3", IsActive: false, Name: "Old", Code: "OLD"},
 	}
-}
 
-func teardown() {
-	server.Close()
-}
+	client := server.NewClient()
+	requestBody := client.AccountingCategories.NewAllRequest()
+	requestBody.ActivityStates = []string{"Active"}
 
-func testMethod(t *testing.T, r *http.Request, expected string) {
-	got := r.Method
-	if expected != got {
-		t.Errorf("Request method = %v, expected %v", got, expected)
+	categories, err := json.CollectAll(client.AccountingCategories.AllIter(requestBody, json.WithPageSize(1)))
+	if err != nil {
+		t.Fatal(err)
 	}
-}
 
-func testHeader(t *testing.T, r *http.Request, key string, expected string) {
-	got := r.Header.Get(key)
-	if expected != got {
-		t.Errorf("Request header %v = %v, expected %v", key, got, expected)
+	if len(categories) != 2 || categories[0].Code != "ACC" || categories[1].Code != "FB" {
+		t.Errorf("got %+v", categories)
 	}
 }
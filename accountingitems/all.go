@@ -1,7 +1,9 @@
 package accountingitems
 
 import (
+	"context"
 	"encoding/json"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -21,31 +23,70 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
 	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
+	apiURL, err := s.Client.GetApiURL(endpointAll)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody := &AllResponse{}
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
+	if err != nil {
 		return nil, err
 	}
 
+	_, err = s.Client.Do(httpReq, responseBody)
+	return responseBody, err
+}
+
+// AllStream is AllContext for responses too large to keep in memory: the
+// accounting items are passed to item as they are decoded instead of being
+// kept in the response, which has the other collections.
+func (s *APIService) AllStream(ctx context.Context, requestBody *AllRequest, item func(AccountingItem) error, opts ...base.RequestOption) (*AllResponse, error) {
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.Client.Do(httpReq, responseBody)
+	_, err = s.Client.Do(httpReq, base.NewStream("AccountingItems", responseBody, item))
 	return responseBody, err
 }
 
+// AllStreamIter is AllIter with AllStream: the accounting items of every page
+// are decoded one at a time.
+func (s *APIService) AllStreamIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[AccountingItem, error] {
+	return base.StreamItems(requestBody, func(r *AllRequest, item func(AccountingItem) error) (*AllResponse, error) {
+		return s.AllStream(r.GetContext(), r, item)
+	}, opts...)
+}
+
+// AllIter iterates over all accounting items, following the response cursor
+// until every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[AccountingItem, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []AccountingItem { return r.AccountingItems }, opts...)
+}
+
 type AllResponse struct {
 	AccountingItems        []AccountingItem
 	OrderItems             OrderItems
 	PaymentItems           PaymentItems
 	CreditCardTransactions CreditCardTransactions
+	Cursor                 string `json:"Cursor"`
+}
+
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
 }
 
 func (s *APIService) NewAllRequest() *AllRequest {
@@ -72,12 +113,31 @@ type AllRequest struct {
 	Currency       string                     `json:"Currency,omitempty"`       // ISO-4217 code of the Currency the item costs should be converted to.
 	Extent         AccountingItemsExtent      `json:"Extent,omitempty"`         // Extent of data to be returned. E.g. it is possible to specify that together with the accounting items, credit card transactions should be also returned.
 	States         []AccountingItemsState     `json:"States,omitempty"`         // States the accounting items should be in. If not specified, accounting items in Open or Closed states are returned.
+	Limitation     base.Limitation            `json:"Limitation,omitempty"`     // Limitation on the quantity of data returned.
+}
+
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
 }
 
 func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+// Validate checks that a filter is set and that the intervals are at most 3
+// months long.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.AnyOf(r.StartUTC != nil || !r.ConsumedUTC.IsEmpty() || !r.ClosedUTC.IsEmpty() || !r.UpdatedUTC.IsEmpty() || len(r.ItemIDs) > 0 || len(r.RebatedItemIDs) > 0,
+		"ConsumedUtc", "ClosedUtc", "UpdatedUtc", "ItemIds", "RebatedItemIds")
+	v.Interval("ConsumedUtc", r.ConsumedUTC.StartUTC, r.ConsumedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ClosedUtc", r.ClosedUTC.StartUTC, r.ClosedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("ItemIds", len(r.ItemIDs), base.MaxIDs)
+	v.MaxItems("RebatedItemIds", len(r.RebatedItemIDs), base.MaxIDs)
+	return v.Err()
+}
+
 type AccountingItemsTimeFilter string
 
 const (
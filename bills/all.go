@@ -1,6 +1,9 @@
 package bills
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -12,18 +15,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +36,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all bills, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Bill, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Bill { return r.Bills }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -69,11 +79,33 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks the lengths of the ID lists and intervals.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.Interval("IssuedUtc", r.IssuedUTC.StartUTC, r.IssuedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("PaidUtc", r.PaidUTC.StartUTC, r.PaidUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("DueUtc", r.DueUTC.StartUTC, r.DueUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("CreatedUtc", r.CreatedUTC.StartUTC, r.CreatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ClosedUtc", r.ClosedUTC.StartUTC, r.ClosedUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("BillIds", len(r.BillIDs), base.MaxIDs)
+	v.MaxItems("CustomerIds", len(r.CustomerIDs), base.MaxIDs)
+	return v.Err()
+}
+
 type AllResponse struct {
 	Bills  Bills  `json:"Bills"` // The closed bills.
 	Cursor string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type BillExtent struct {
 	Items bool `json:"Items"`
 }
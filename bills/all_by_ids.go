@@ -1,6 +1,10 @@
 package bills
 
-import "github.com/omniboost/go-mews/json"
+import (
+	"context"
+
+	"github.com/omniboost/go-mews/json"
+)
 
 const (
 	endpointAllByIDs = "bills/getAllByIds"
@@ -8,18 +12,28 @@ const (
 
 // List all products
 func (s *Service) AllByIDs(requestBody *AllByIDsRequest) (*AllByIDsResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.AllByIDsContext(requestBody.GetContext(), requestBody)
+}
+
+// AllByIDsContext is AllByIDs with a context and per-call options. More than
+// json.MaxIDs BillIDs are fetched in chunks and merged into one response.
+func (s *Service) AllByIDsContext(ctx context.Context, requestBody *AllByIDsRequest, opts ...json.RequestOption) (*AllByIDsResponse, error) {
+	if len(requestBody.BillIDs) > json.MaxIDs {
+		return json.FetchChunks(ctx, requestBody.BillIDs, json.MaxIDs, func(ctx context.Context, ids []string) (*AllByIDsResponse, error) {
+			chunk := *requestBody
+			chunk.BillIDs = ids
+			return s.AllByIDsContext(ctx, &chunk, opts...)
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllByIDs)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllByIDsResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,7 @@
 package bills
 
 import (
+	"context"
 	"time"
 
 	"encoding/json"
@@ -15,18 +16,19 @@ const (
 
 // List all products
 func (s *Service) AllClosed(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllClosedContext(requestBody.GetContext(), requestBody)
+}
 
+// AllClosedContext is AllClosed with a context and per-call options.
+func (s *Service) AllClosedContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllClosed)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -53,6 +55,7 @@ type Bills []Bill
 
 type Bill struct {
 	ID                    string                     `json:"Id"`                    // Unique identifier of the bill.
+	EnterpriseID          string                     `json:"EnterpriseId"`          // Unique identifier of the Enterprise.
 	CustomerID            string                     `json:"CustomerId"`            // Unique identifier of the Customer the bill is issued to.
 	CompanyID             string                     `json:"CompanyId"`             // Unique identifier of the Company the bill is issued to.
 	CounterID             string                     `json:"CounterId"`             // Unique identifier of the bill Counter.
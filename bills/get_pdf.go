@@ -1,6 +1,7 @@
 package bills
 
 import (
+	"context"
 	gojson "encoding/json"
 	"errors"
 	"fmt"
@@ -15,18 +16,19 @@ const (
 
 // List all products
 func (s *Service) GetPDF(requestBody *GetPDFRequest) (*GetPDFResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.GetPDFContext(requestBody.GetContext(), requestBody)
+}
 
+// GetPDFContext is GetPDF with a context and per-call options.
+func (s *Service) GetPDFContext(ctx context.Context, requestBody *GetPDFRequest, opts ...json.RequestOption) (*GetPDFResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointGetPDF)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &GetPDFResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
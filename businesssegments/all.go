@@ -1,6 +1,9 @@
 package businesssegments
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 )
@@ -11,18 +14,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -31,6 +35,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all business segments, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[BusinessSegment, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []BusinessSegment { return r.BusinessSegments }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -45,6 +55,10 @@ type AllRequest struct {
 	Limitation     json.Limitation             `json:"Limitation,omitempty"`
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type ActivityStates []ActivityState
 
 type ActivityState string
@@ -54,6 +68,10 @@ type AllResponse struct {
 	Cursor           string           `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type BusinessSegments []BusinessSegment
 
 type BusinessSegment struct {
@@ -0,0 +1,100 @@
+package mews_test
+
+import (
+	"context"
+	gojson "encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	mews "github.com/omniboost/go-mews"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+)
+
+// newCallServer returns a server with a tasks/getAll endpoint that echoes the
+// request body it received.
+func newCallServer(t *testing.T) (*mewstest.Server, *mews.Client) {
+	server := mewstest.NewServer()
+	t.Cleanup(server.Close)
+	server.Handle("tasks/getAll", func(body []byte) (interface{}, error) {
+		return struct{ Received gojson.RawMessage }{body}, nil
+	})
+
+	client := server.NewClient()
+	client.SetRetryPolicy(&json.BackoffRetryPolicy{MaxRetries: 3})
+	return server, client
+}
+
+type echo struct {
+	Received map[string]interface{}
+}
+
+func TestCall(t *testing.T) {
+	server, client := newCallServer(t)
+	client.SetLanguageCode("nl-NL")
+
+	type tasksRequest struct {
+		TaskIDs []string `json:"TaskIds"`
+	}
+	bodies := map[string]interface{}{
+		"struct": tasksRequest{TaskIDs: []string{"t1"}},
+		"map":    map[string]interface{}{"TaskIds": []string{"t1"}},
+		"raw":    gojson.RawMessage(`{"TaskIds":["t1"]}`),
+	}
+	for name, body := range bodies {
+		t.Run(name, func(t *testing.T) {
+			resp := echo{}
+			if err := client.Call(context.Background(), "tasks/getAll", body, &resp); err != nil {
+				t.Fatal(err)
+			}
+
+			r := resp.Received
+			if r["AccessToken"] != server.AccessToken || r["ClientToken"] != server.ClientToken {
+				t.Errorf("tokens weren't injected: %v", r)
+			}
+			if r["LanguageCode"] != "nl-NL" {
+				t.Errorf("LanguageCode = %v, expected nl-NL", r["LanguageCode"])
+			}
+			if ids, _ := r["TaskIds"].([]interface{}); len(ids) != 1 || ids[0] != "t1" {
+				t.Errorf("TaskIds = %v, expected the body of the caller", r["TaskIds"])
+			}
+		})
+	}
+}
+
+func TestCallRaw(t *testing.T) {
+	server, client := newCallServer(t)
+	server.Fail("tasks/getAll", mewstest.Fault{Status: http.StatusServiceUnavailable})
+
+	raw, err := client.CallRaw(context.Background(), "/tasks/getAll", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := server.Requests("tasks/getAll"); n != 2 {
+		t.Errorf("%d requests, expected the failed one to be retried", n)
+	}
+
+	resp := echo{}
+	if err := gojson.Unmarshal(raw, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Received["AccessToken"] != server.AccessToken {
+		t.Errorf("tokens weren't injected: %s", raw)
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	server, client := newCallServer(t)
+	server.Fail("tasks/getAll", mewstest.Fault{Status: http.StatusBadRequest, Message: "Invalid TaskIds."})
+
+	err := client.Call(context.Background(), "tasks/getAll", map[string]interface{}{}, &echo{})
+	if !errors.Is(err, json.ErrValidation) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	err = client.Call(context.Background(), "tasks/getAll", []string{"t1"}, &echo{})
+	if err == nil {
+		t.Error("expected an error for a body that isn't an object")
+	}
+}
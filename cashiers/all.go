@@ -1,6 +1,8 @@
 package cashiers
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/json"
@@ -13,18 +15,19 @@ const (
 
 // List all cashiers
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +36,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all cashiers, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[Cashier, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []Cashier { return r.Cashiers }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -46,11 +55,19 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type AllResponse struct {
 	Cashiers Cashiers `json:"Cashiers"`
 	Cursor   string   `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type Cashiers []Cashier
 
 type Cashier struct {
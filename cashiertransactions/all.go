@@ -1,6 +1,8 @@
 package cashiertransactions
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/accountingitems"
@@ -15,18 +17,33 @@ const (
 
 // List all cashier transactions
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options. A CreatedUTC interval
+// longer than json.MaxIntervalMonths is split into windows, whose pages are
+// all fetched and merged into one response without a cursor.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	if windows := json.Windows(requestBody.CreatedUTC.StartUTC, requestBody.CreatedUTC.EndUTC, json.MaxIntervalMonths); len(windows) > 1 {
+		return json.FetchWindows(ctx, windows, func(ctx context.Context, w json.Window) (*AllResponse, error) {
+			window := *requestBody
+			window.CreatedUTC = configuration.TimeInterval{StartUTC: w.Start, EndUTC: w.End}
+			window.Limitation.Cursor = ""
+			window.SetContext(ctx)
+			return json.MergePages(&window, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +52,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all cashier transactions, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[CashierTransaction, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []CashierTransaction { return r.CashierTransactions }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -49,11 +72,19 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type AllResponse struct {
 	CashierTransactions CashierTransactions `json:"CashierTransactions"`
 	Cursor              string              `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type CashierTransactions []CashierTransaction
 
 type CashierTransaction struct {
@@ -2,6 +2,8 @@ package mews
 
 import (
 	"context"
+	gojson "encoding/json"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"time"
@@ -47,6 +49,7 @@ import (
 const (
 	libraryVersion = "0.0.1"
 	userAgent      = "go-mews/" + libraryVersion
+	clientName     = "go-mews " + libraryVersion
 )
 
 var (
@@ -74,14 +77,14 @@ func NewClient(httpClient *http.Client, accessToken string, clientToken string)
 	jsonClient.ClientToken = clientToken
 	jsonClient.Debug = false
 	jsonClient.Timeout = 60 * time.Second
-	jsonClient.RetryOnTimeout = false
-	jsonClient.MaxRetries = 3
+	jsonClient.RetryPolicy = json.DefaultRetryPolicy()
+	jsonClient.Limiter = json.NewRateLimiter(json.DefaultRateLimit)
 
 	c := &Client{
 		client: jsonClient,
 	}
 
-	c.SetBaseURL(BaseURL)
+	c.SetEnvironment(Production)
 
 	// Services
 	c.AccountingItems = accountingitems.NewService()
@@ -158,11 +161,18 @@ func NewClient(httpClient *http.Client, accessToken string, clientToken string)
 	return c
 }
 
-// Client manages communication with MEWS API
+// Client manages communication with MEWS API. A Client is safe for concurrent
+// use by multiple goroutines. The Set methods configure all calls and belong
+// before the first request, except SetLanguageCode and SetCultureCode; settings
+// that differ between calls are passed as options to the Context variants of
+// the service methods, e.g. json.WithCultureCode.
 type Client struct {
 	// HTTP client used to communicate with the API.
 	client *json.Client
 
+	// Websocket endpoint of the environment
+	websocketURL *url.URL
+
 	// Services used for communicating with the API
 	AccountingItems       *accountingitems.APIService
 	AgeCategories         *agecategories.Service
@@ -201,42 +211,196 @@ type Client struct {
 	Devices               *devices.Service
 }
 
+// SetDebug dumps the requests and responses of all calls. Use json.WithDebug
+// for a single call.
 func (c *Client) SetDebug(debug bool) {
 	c.client.Debug = debug
 }
 
+// SetLogger sets a structured logger that logs every request. Tokens and card
+// data are always redacted.
+func (c *Client) SetLogger(logger *slog.Logger) {
+	c.client.Logger = logger
+}
+
+// SetLogBodies enables logging of request and response bodies, truncated to
+// maxSize bytes. A maxSize of 0 uses json.DefaultMaxLogBodySize.
+func (c *Client) SetLogBodies(logBodies bool, maxSize int) {
+	c.client.LogBodies = logBodies
+	c.client.MaxLogBodySize = maxSize
+}
+
+// SetBaseURL points the client at another Connector API. When baseURL belongs
+// to a known environment, the websocket endpoint follows it. Otherwise the
+// client has no websocket endpoint until SetEnvironment sets one.
 func (c *Client) SetBaseURL(baseURL *url.URL) {
 	c.client.BaseURL = baseURL
+	c.websocketURL = nil
+	if env, ok := environmentOf(baseURL); ok {
+		c.websocketURL = env.WebsocketURL
+	}
 }
 
+// SetEnvironment points the REST and websocket endpoints at env.
+func (c *Client) SetEnvironment(env Environment) {
+	c.client.BaseURL = env.BaseURL
+	c.websocketURL = env.WebsocketURL
+}
+
+// SetTokenProvider resolves the tokens of every request and websocket
+// connection with provider, instead of the tokens passed to NewClient.
+func (c *Client) SetTokenProvider(provider json.TokenProvider) {
+	c.client.TokenProvider = provider
+}
+
+// SetPortfolio marks the access token as a portfolio access token. Writes then
+// fail with json.ErrNoEnterprise unless they name their enterprise, in the
+// request or with json.WithEnterpriseID.
+func (c *Client) SetPortfolio(portfolio bool) {
+	c.client.Portfolio = portfolio
+}
+
+// SetClientName sets the Client of every request, the name and version of the
+// integration.
+func (c *Client) SetClientName(name string) {
+	c.client.ClientName = name
+}
+
+// SetTimeout limits every attempt of all calls to timeout. Use
+// json.WithTimeout for a single call. The http.Client passed to NewClient,
+// which may be shared, isn't changed.
 func (c *Client) SetTimeout(timeout time.Duration) {
-	c.client.Client.Timeout = timeout
+	c.client.Timeout = timeout
 }
 
+// SetRetryOnTimeout enables or disables retrying requests that timed out.
+//
+// Deprecated: use SetRetryPolicy.
 func (c *Client) SetRetryOnTimeout(retryOnTimeout bool) {
 	c.client.RetryOnTimeout = retryOnTimeout
+	if p, ok := c.client.RetryPolicy.(*json.BackoffRetryPolicy); ok {
+		p.RetryTimeouts = retryOnTimeout
+	}
+}
+
+// SetRetryPolicy replaces the retry policy. Passing nil disables retries. Use
+// json.WithRetryPolicy for a single call.
+func (c *Client) SetRetryPolicy(policy json.RetryPolicy) {
+	c.client.RetryPolicy = policy
 }
 
 func (c *Client) SetDisallowUnknownFields(disallowUnknownFields bool) {
 	c.client.DisallowUnknownFields = disallowUnknownFields
 }
 
+// SetReportUnknownFields decodes responses with fields the library doesn't
+// know instead of failing, and collects those fields. See DriftReport.
+func (c *Client) SetReportUnknownFields(report bool) {
+	c.client.ReportUnknownFields = report
+}
+
+// DriftReport returns the unknown response fields found per endpoint and type
+// while SetReportUnknownFields is enabled.
+func (c *Client) DriftReport() []json.Drift {
+	return c.client.DriftReport()
+}
+
+// OnDrift sets the callback that is called the first time an unknown response
+// field is found.
+func (c *Client) OnDrift(dc json.DriftCallback) {
+	c.client.OnDrift(dc)
+}
+
+// SetSkipValidation sends requests without checking the documented
+// constraints of their endpoint first. See json.Validate.
+func (c *Client) SetSkipValidation(skip bool) {
+	c.client.SkipValidation = skip
+}
+
+// SetCompression configures compression. Responses are requested compressed
+// unless disableResponses is set. Request bodies of at least requestsOver bytes
+// are gzip compressed, 0 never compresses them.
+func (c *Client) SetCompression(disableResponses bool, requestsOver int) {
+	c.client.DisableCompression = disableResponses
+	c.client.CompressRequestsOver = requestsOver
+}
+
+// OnTransfer sets the callback that receives the compressed and uncompressed
+// body sizes of every attempt.
+func (c *Client) OnTransfer(tc json.TransferCallback) {
+	c.client.OnTransfer(tc)
+}
+
+// Use adds middleware around every request made by the services of the
+// client. See json.Middleware.
+func (c *Client) Use(middleware ...json.Middleware) {
+	c.client.Use(middleware...)
+}
+
+// SetLimiter replaces the client side rate limiter. Passing nil disables it.
+func (c *Client) SetLimiter(limiter json.Limiter) {
+	c.client.Limiter = limiter
+}
+
+// LimiterState reports the state of the rate limiter for the access token of
+// the client, as resolved by its TokenProvider. It returns false when the
+// limiter doesn't report its state.
+func (c *Client) LimiterState() (json.LimiterState, bool) {
+	l, ok := c.client.Limiter.(interface {
+		State(token string) json.LimiterState
+	})
+	if !ok {
+		return json.LimiterState{}, false
+	}
+	token := c.client.AccessToken
+	if tokens, err := c.client.Tokens(context.Background()); err == nil {
+		token = tokens.AccessToken
+	}
+	return l.State(token), true
+}
+
+// SetLanguageCode sets the default language of all calls. Use
+// json.WithLanguageCode for a single call.
 func (c *Client) SetLanguageCode(code string) {
 	c.client.SetLanguageCode(code)
 }
 
+// SetCultureCode sets the default culture of all calls. Use
+// json.WithCultureCode for a single call.
 func (c *Client) SetCultureCode(code string) {
 	c.client.SetCultureCode(code)
 }
 
+// Call sends req to an endpoint the library doesn't wrap yet, e.g.
+// "tasks/getAll", and decodes the response into resp. req can be any struct or
+// map; the tokens, language and culture are added to it and the call is
+// limited, retried and checked for errors like the calls of the services.
+func (c *Client) Call(ctx context.Context, path string, req interface{}, resp interface{}, opts ...json.RequestOption) error {
+	_, err := c.client.Call(ctx, path, req, resp, opts...)
+	return err
+}
+
+// CallRaw is Call with a raw JSON request and response body.
+func (c *Client) CallRaw(ctx context.Context, path string, req gojson.RawMessage, opts ...json.RequestOption) (gojson.RawMessage, error) {
+	return c.client.CallRaw(ctx, path, req, opts...)
+}
+
+// GetWebsocket returns a websocket for the environment of the client. When the
+// environment has no websocket endpoint, Connect fails with
+// ErrNoWebsocketURL.
 func (c *Client) GetWebsocket(ctx context.Context) *Websocket {
 	ws := NewWebsocket(c.client.Client, c.client.AccessToken, c.client.ClientToken)
-	url := &url.URL{
-		Scheme: WebsocketURL.Scheme,
-		Host:   WebsocketURL.Host,
-		Path:   WebsocketURL.Path,
+	var u *url.URL
+	if c.websocketURL != nil {
+		u = &url.URL{
+			Scheme: c.websocketURL.Scheme,
+			Host:   c.websocketURL.Host,
+			Path:   c.websocketURL.Path,
+		}
 	}
-	ws.SetBaseURL(url)
+	ws.SetBaseURL(u)
+	ws.SetTokenProvider(c.client.TokenProvider)
 	ws.SetDebug(c.client.Debug)
+	ws.SetLogger(c.client.Logger)
 	return ws
 }
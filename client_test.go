@@ -1,7 +1,7 @@
 package mews_test
 
 import (
-	"log"
+	"errors"
 	"net/url"
 	"os"
 	"testing"
@@ -12,10 +12,37 @@ import (
 	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/ledgerbalances"
 	"github.com/omniboost/go-mews/ledgerentries"
+	"github.com/omniboost/go-mews/mewstest"
 	"github.com/omniboost/go-mews/reservations"
 )
 
-func getClient() *mews.Client {
+var (
+	// fixed so the requests match the recorded cassettes
+	replayNow = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// getClient returns a client that replays the cassette of the test from
+// testdata/cassettes, and the time the requests of the test are relative to.
+// Record it with MEWS_RECORD=1 and MEWS_ACCESS_TOKEN, MEWS_CLIENT_TOKEN and
+// optionally MEWS_BASE_URL set. Without a cassette the test runs against the
+// API when MEWS_ACCESS_TOKEN is set, and is skipped otherwise.
+func getClient(t *testing.T) (*mews.Client, time.Time) {
+	now := replayNow
+	var client *mews.Client
+	if _, err := os.Stat(mewstest.CassettePath(t.Name())); errors.Is(err, os.ErrNotExist) &&
+		mewstest.ModeFromEnv() == mewstest.ModeReplay && os.Getenv("MEWS_ACCESS_TOKEN") != "" {
+		client = getLiveClient(t)
+		now = time.Now()
+	} else {
+		client = mewstest.NewClient(t, t.Name())
+	}
+	client.SetDebug(true)
+	client.SetDisallowUnknownFields(true)
+
+	return client, now
+}
+
+func getLiveClient(t *testing.T) *mews.Client {
 	// get username & password
 	accessToken := os.Getenv("MEWS_ACCESS_TOKEN")
 	clientToken := os.Getenv("MEWS_CLIENT_TOKEN")
@@ -23,25 +50,23 @@ func getClient() *mews.Client {
 
 	// build client
 	client := mews.NewClient(nil, accessToken, clientToken)
-	client.SetDebug(true)
 
 	if baseURL != "" {
 		u, err := url.Parse(baseURL)
 		if err != nil {
-			log.Fatal(err)
+			t.Fatal(err)
 		}
 		client.SetBaseURL(u)
 	}
-	client.SetDisallowUnknownFields(true)
 
 	return client
 }
 
 func TestAccountingItems(t *testing.T) {
-	client := getClient()
+	client, now := getClient(t)
 
-	startUTC := time.Now().AddDate(0, 0, -1)
-	endUTC := time.Now()
+	startUTC := now.AddDate(0, 0, -1)
+	endUTC := now
 
 	requestBody := &accountingitems.AllRequest{}
 	requestBody.StartUTC = &startUTC
@@ -54,10 +79,10 @@ func TestAccountingItems(t *testing.T) {
 }
 
 func TestReservations(t *testing.T) {
-	client := getClient()
+	client, now := getClient(t)
 
-	startUTC := time.Now().AddDate(0, 0, -1)
-	endUTC := time.Now()
+	startUTC := now.AddDate(0, 0, -1)
+	endUTC := now
 
 	requestBody := &reservations.AllRequest{}
 	requestBody.StartUTC = &startUTC
@@ -81,7 +106,7 @@ func TestReservations(t *testing.T) {
 }
 
 func TestConfig(t *testing.T) {
-	client := getClient()
+	client, _ := getClient(t)
 
 	requestBody := client.Configuration.NewGetRequest()
 	_, err := client.Configuration.Get(requestBody)
@@ -91,10 +116,10 @@ func TestConfig(t *testing.T) {
 }
 
 func TestLedgerBalances(t *testing.T) {
-	client := getClient()
+	client, now := getClient(t)
 
-	start := time.Now().AddDate(0, -1, 0)
-	end := time.Now()
+	start := now.AddDate(0, -1, 0)
+	end := now
 
 	requestBody := &ledgerbalances.AllRequest{}
 	requestBody.Date.Start = base.Date{Time: start}
@@ -112,10 +137,10 @@ func TestLedgerBalances(t *testing.T) {
 }
 
 func TestLedgerEntries(t *testing.T) {
-	client := getClient()
+	client, now := getClient(t)
 
-	start := time.Now().AddDate(0, -1, 0)
-	end := time.Now()
+	start := now.AddDate(0, -1, 0)
+	end := now
 
 	requestBody := &ledgerentries.AllRequest{}
 	requestBody.PostingDate.Start = base.Date{Time: start}
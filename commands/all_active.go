@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"context"
 	"time"
 
 	"github.com/omniboost/go-mews/bills"
@@ -14,18 +15,19 @@ const (
 
 // List all products
 func (s *Service) AllActive(requestBody *AllActiveRequest) (*AllActiveResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllActiveContext(requestBody.GetContext(), requestBody)
+}
 
+// AllActiveContext is AllActive with a context and per-call options.
+func (s *Service) AllActiveContext(ctx context.Context, requestBody *AllActiveRequest, opts ...json.RequestOption) (*AllActiveResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllActive)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllActiveResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
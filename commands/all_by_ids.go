@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"context"
+
 	"github.com/omniboost/go-mews/json"
 )
 
@@ -10,18 +12,19 @@ const (
 
 // List all commands
 func (s *Service) AllByIDs(requestBody *AllByIDsRequest) (*AllByIDsResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllByIDsContext(requestBody.GetContext(), requestBody)
+}
 
+// AllByIDsContext is AllByIDs with a context and per-call options.
+func (s *Service) AllByIDsContext(ctx context.Context, requestBody *AllByIDsRequest, opts ...json.RequestOption) (*AllByIDsResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllByIDs)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllByIDsResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,10 @@
 package commands
 
-import "github.com/omniboost/go-mews/json"
+import (
+	"context"
+
+	"github.com/omniboost/go-mews/json"
+)
 
 const (
 	endpointUpdate = "commands/update"
@@ -8,18 +12,19 @@ const (
 
 // List all products
 func (s *Service) Update(requestBody *UpdateRequest) (*UpdateResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.UpdateContext(requestBody.GetContext(), requestBody)
+}
 
+// UpdateContext is Update with a context and per-call options.
+func (s *Service) UpdateContext(ctx context.Context, requestBody *UpdateRequest, opts ...json.RequestOption) (*UpdateResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointUpdate)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &UpdateResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
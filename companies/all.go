@@ -1,6 +1,8 @@
 package companies
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +37,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all companies, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Company, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Company { return r.Companies }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -52,11 +61,19 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type AllResponse struct {
 	Companies Companies `json:"companies"`
 	Cursor    string    `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type CompanyOptions struct {
 	Invoiceable                     bool `json:"Invoiceable"`
 	AddFeesToInvoices               bool `json:"AddFeesToInvoices"`
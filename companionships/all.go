@@ -1,6 +1,9 @@
 package companionships
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/customers"
 	base "github.com/omniboost/go-mews/json"
@@ -15,18 +18,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +39,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all companionships, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Companionship, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Companionship { return r.Companionships }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -68,6 +78,10 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type AllResponse struct {
 	Cursor string `json:"Cursor"`
 
@@ -77,6 +91,10 @@ type AllResponse struct {
 	ReservationGroups reservationgroups.ReservationGroups `json:"ReservationGroups"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type companionships []Companionship
 
 type Companionship struct {
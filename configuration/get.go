@@ -1,6 +1,7 @@
 package configuration
 
 import (
+	"context"
 	"time"
 
 	base "github.com/omniboost/go-mews/json"
@@ -13,18 +14,19 @@ const (
 
 // Returns configuration of the enterprise and the client.
 func (s *Service) Get(requestBody *GetRequest) (*GetResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.GetContext(requestBody.GetContext(), requestBody)
+}
 
+// GetContext is Get with a context and per-call options.
+func (s *Service) GetContext(ctx context.Context, requestBody *GetRequest, opts ...base.RequestOption) (*GetResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointGet)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &GetResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,10 @@
 package configuration
 
-import "github.com/omniboost/go-mews/json"
+import (
+	"context"
+
+	"github.com/omniboost/go-mews/json"
+)
 
 const (
 	endpointTaxationsGetAll = "taxations/getAll"
@@ -8,18 +12,19 @@ const (
 
 // List all products
 func (s *Service) TaxationsGetAll(requestBody *TaxationsGetAllRequest) (*TaxationsGetAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.TaxationsGetAllContext(requestBody.GetContext(), requestBody)
+}
 
+// TaxationsGetAllContext is TaxationsGetAll with a context and per-call options.
+func (s *Service) TaxationsGetAllContext(ctx context.Context, requestBody *TaxationsGetAllRequest, opts ...json.RequestOption) (*TaxationsGetAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointTaxationsGetAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &TaxationsGetAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
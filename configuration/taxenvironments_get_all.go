@@ -1,6 +1,7 @@
 package configuration
 
 import (
+	"context"
 	"time"
 
 	"github.com/omniboost/go-mews/json"
@@ -17,18 +18,19 @@ var (
 
 // List all products
 func (s *Service) TaxenvironmentsGetAll(requestBody *TaxenvironmentsGetAllRequest) (*TaxenvironmentsGetAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.TaxenvironmentsGetAllContext(requestBody.GetContext(), requestBody)
+}
 
+// TaxenvironmentsGetAllContext is TaxenvironmentsGetAll with a context and per-call options.
+func (s *Service) TaxenvironmentsGetAllContext(ctx context.Context, requestBody *TaxenvironmentsGetAllRequest, opts ...json.RequestOption) (*TaxenvironmentsGetAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointTaxenvironmentsGetAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &TaxenvironmentsGetAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
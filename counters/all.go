@@ -1,6 +1,8 @@
 package counters
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -13,18 +15,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,11 +36,21 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all counters, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[Counter, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []Counter { return r.Counters }, opts...)
+}
+
 type AllResponse struct {
 	Counters []Counter
 	Cursor   string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{
 		Limitation: &json.Limitation{},
@@ -53,6 +66,13 @@ type AllRequest struct {
 	Limitation    *json.Limitation            `json:"Limitation,omitempty"`    // Limitation on the quantity of data returned.
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	if r.Limitation == nil {
+		r.Limitation = &json.Limitation{}
+	}
+	return r.Limitation
+}
+
 type Counter struct {
 	ID         string      `json:"Id"`         // Unique identifier of the counter.
 	Name       string      `json:"Name"`       // Name of the counter
@@ -1,6 +1,9 @@
 package creditcards
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -12,18 +15,19 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +36,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all credit cards, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[CreditCard, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []CreditCard { return r.CreditCards }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -49,7 +59,27 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks that a filter is set and the lengths of the ID lists and
+// intervals.
+func (r *AllRequest) Validate() error {
+	v := &json.Validation{}
+	v.AnyOf(len(r.CreditCardIDs) > 0 || len(r.CustomerIDs) > 0 || !r.UpdatedUTC.IsEmpty(),
+		"CreditCardIds", "CustomerIds", "UpdatedUtc")
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, json.MaxIntervalMonths)
+	v.MaxItems("CreditCardIds", len(r.CreditCardIDs), json.MaxIDs)
+	v.MaxItems("CustomerIds", len(r.CustomerIDs), json.MaxIDs)
+	return v.Err()
+}
+
 type AllResponse struct {
 	CreditCards CreditCards `json:"CreditCards"` // The credit cards.
 	Cursor      string      `json:"Cursor"`      // Unique identifier of the item one newer in time order than the items to be returned. If Cursor is not specified, i.e. null, then the latest or most recent items will be returned.
 }
+
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
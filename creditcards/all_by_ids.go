@@ -1,6 +1,10 @@
 package creditcards
 
-import "github.com/omniboost/go-mews/json"
+import (
+	"context"
+
+	"github.com/omniboost/go-mews/json"
+)
 
 const (
 	endpointAllByIDs = "creditCards/getAllByIds"
@@ -8,18 +12,28 @@ const (
 
 // List all products
 func (s *Service) AllByIDs(requestBody *AllByIDsRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.AllByIDsContext(requestBody.GetContext(), requestBody)
+}
+
+// AllByIDsContext is AllByIDs with a context and per-call options. More than
+// json.MaxIDs CreditCardIDs are fetched in chunks and merged into one response.
+func (s *Service) AllByIDsContext(ctx context.Context, requestBody *AllByIDsRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	if len(requestBody.CreditCardIDs) > json.MaxIDs {
+		return json.FetchChunks(ctx, requestBody.CreditCardIDs, json.MaxIDs, func(ctx context.Context, ids []string) (*AllResponse, error) {
+			chunk := *requestBody
+			chunk.CreditCardIDs = ids
+			return s.AllByIDsContext(ctx, &chunk, opts...)
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllByIDs)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package customers
 
 import (
+	"context"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 )
@@ -11,18 +13,19 @@ const (
 
 // Add customer
 func (s *Service) Add(requestBody *AddRequest) (*AddResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AddContext(requestBody.GetContext(), requestBody)
+}
 
+// AddContext is Add with a context and per-call options.
+func (s *Service) AddContext(ctx context.Context, requestBody *AddRequest, opts ...json.RequestOption) (*AddResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAdd)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AddResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package customers
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -15,18 +17,33 @@ const (
 
 // List all products
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options. An UpdatedUTC
+// interval longer than base.MaxIntervalMonths is split into windows, whose
+// pages are all fetched and merged into one response without a cursor.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	if windows := base.Windows(requestBody.UpdatedUTC.StartUTC, requestBody.UpdatedUTC.EndUTC, base.MaxIntervalMonths); len(windows) > 1 {
+		return base.FetchWindows(ctx, windows, func(ctx context.Context, w base.Window) (*AllResponse, error) {
+			window := *requestBody
+			window.UpdatedUTC = configuration.TimeInterval{StartUTC: w.Start, EndUTC: w.End}
+			window.Limitation.Cursor = ""
+			window.SetContext(ctx)
+			return base.MergePages(&window, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +52,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all customers, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Customer, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Customer { return r.Customers }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{
 		Extent: CustomersExtent{
@@ -65,11 +88,31 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks the lengths of the ID lists and intervals.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.Interval("CreatedUtc", r.CreatedUTC.StartUTC, r.CreatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("DeletedUtc", r.DeletedUTC.StartUTC, r.DeletedUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("CustomerIds", len(r.CustomerIDs), base.MaxIDs)
+	v.MaxItems("CompanyIds", len(r.CompanyIDs), base.MaxIDs)
+	v.MaxItems("Emails", len(r.Emails), base.MaxIDs)
+	return v.Err()
+}
+
 type AllResponse struct {
 	Customers Customers `json:"customers"`
 	Cursor    string    `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type CustomersExtent struct {
 	Customers bool `json:"Customers"` // Whether the response should contain information about customers.
 	Addresses bool `json:"Addresses"` // Whether the response should contain addresses of customers.
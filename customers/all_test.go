@@ -0,0 +1,74 @@
+package customers_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+)
+
+func TestAllIter(t *testing.T) {
+	client := mewstest.NewClient(t, "customers_all")
+
+	requestBody := client.Customers.NewAllRequest()
+	requestBody.UpdatedUTC = configuration.TimeInterval{
+		StartUTC: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndUTC:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	customers, err := json.CollectAll(client.Customers.AllIter(requestBody, json.WithPageSize(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(customers) != 3 {
+		t.Fatalf("len(customers) = %d, expected 3", len(customers))
+	}
+	if customers[2].LastName != "Hopper" {
+		t.Errorf("customers[2].LastName = %q, expected %q", customers[2].LastName, "Hopper")
+	}
+}
+
+func TestAllSplitsLongIntervals(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 12; i++ {
+		server.Store.Customers = append(server.Store.Customers, customers.Customer{
+			ID:         fmt.Sprintf("customer-%d", i),
+			UpdatedUTC: start.AddDate(0, i, 0),
+		})
+	}
+
+	client := server.NewClient()
+	requestBody := client.Customers.NewAllRequest()
+	requestBody.UpdatedUTC = configuration.TimeInterval{
+		StartUTC: start,
+		EndUTC:   start.AddDate(1, 0, 0),
+	}
+	requestBody.Limitation.Count = 2
+
+	resp, err := client.Customers.AllContext(context.Background(), requestBody, json.WithWindowConcurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// customers updated at the bounds of the windows are returned twice by the
+	// API but listed once
+	if len(resp.Customers) != 12 {
+		t.Errorf("len(Customers) = %d, expected 12", len(resp.Customers))
+	}
+	if resp.Cursor != "" {
+		t.Errorf("Cursor = %q, expected none", resp.Cursor)
+	}
+	// 4 windows of 3 months, with 2 or 3 pages each
+	if n := server.Requests("customers/getAll"); n < 8 {
+		t.Errorf("%d requests, expected every page of 4 windows", n)
+	}
+}
@@ -1,6 +1,9 @@
 package devices
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
 )
@@ -11,17 +14,18 @@ const (
 
 // List all commands
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -30,6 +34,12 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all devices, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[Device, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []Device { return r.Devices }, opts...)
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -43,11 +53,19 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type AllResponse struct {
 	Devices Devices `json:"Devices"`
 	Cursor  string  `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type Devices []Device
 
 type Device struct {
@@ -0,0 +1,82 @@
+package enterprises
+
+import (
+	"context"
+	"iter"
+
+	"github.com/omniboost/go-mews/configuration"
+	base "github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/omitempty"
+)
+
+const (
+	endpointAll = "enterprises/getAll"
+)
+
+// All returns the enterprises in scope of the access token, every enterprise
+// of the portfolio for a portfolio access token.
+func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	apiURL, err := s.Client.GetApiURL(endpointAll)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody := &AllResponse{}
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.Client.Do(httpReq, responseBody)
+	return responseBody, err
+}
+
+// AllIter iterates over all enterprises, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[configuration.Enterprise, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []configuration.Enterprise { return r.Enterprises }, opts...)
+}
+
+func (s *APIService) NewAllRequest() *AllRequest {
+	return &AllRequest{}
+}
+
+type AllRequest struct {
+	base.BaseRequest
+
+	// Limitation on the quantity of enterprises returned.
+	Limitation base.Limitation `json:"Limitation,omitempty"`
+
+	// Unique identifiers of the Enterprises. If not specified, the operation
+	// returns all enterprises within scope of the Access Token.
+	EnterpriseIDs []string `json:"EnterpriseIds,omitempty"`
+	// Identifiers of the enterprises in external systems.
+	ExternalIdentifiers []string `json:"ExternalIdentifiers,omitempty"`
+	// Interval in which the enterprise was added to the portfolio.
+	LinkedUTC configuration.TimeInterval `json:"LinkedUtc,omitempty"`
+	// Interval in which the enterprise was updated.
+	UpdatedUTC configuration.TimeInterval `json:"UpdatedUtc,omitempty"`
+}
+
+func (r AllRequest) MarshalJSON() ([]byte, error) {
+	return omitempty.MarshalJSON(r)
+}
+
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+type AllResponse struct {
+	Cursor string `json:"Cursor"`
+
+	Enterprises []configuration.Enterprise `json:"Enterprises"`
+}
+
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
@@ -0,0 +1,35 @@
+package enterprises_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+)
+
+func TestAll(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+
+	linked := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	server.Store.Enterprises = []configuration.Enterprise{
+		{ID: "1", Name: "Hotel Amsterdam", TimeZoneIdentifier: "Europe/Amsterdam", LinkedUTC: linked},
+		{ID: "2", Name: "Hotel Prague", TimeZoneIdentifier: "Europe/Prague", LinkedUTC: linked.AddDate(0, 0, 10)},
+		{ID: "3", Name: "Hotel Lisbon", TimeZoneIdentifier: "Europe/Lisbon", LinkedUTC: linked.AddDate(0, 2, 0)},
+	}
+
+	client := server.NewClient()
+	requestBody := client.Enterprises.NewAllRequest()
+	requestBody.LinkedUTC = configuration.TimeInterval{StartUTC: linked, EndUTC: linked.AddDate(0, 1, 0)}
+
+	enterprises, err := json.CollectAll(client.Enterprises.AllIter(requestBody, json.WithPageSize(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(enterprises) != 2 || enterprises[0].TimeZoneIdentifier != "Europe/Amsterdam" || enterprises[1].Name != "Hotel Prague" {
+		t.Errorf("got %+v", enterprises)
+	}
+}
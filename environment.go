@@ -0,0 +1,45 @@
+package mews
+
+import "net/url"
+
+// Environment is a Mews deployment: the endpoints of its Connector API and
+// its websocket.
+type Environment struct {
+	Name         string
+	BaseURL      *url.URL
+	WebsocketURL *url.URL
+}
+
+var (
+	Production = Environment{
+		Name:         "production",
+		BaseURL:      BaseURL,
+		WebsocketURL: WebsocketURL,
+	}
+	Demo = Environment{
+		Name:         "demo",
+		BaseURL:      BaseURLDemo,
+		WebsocketURL: WebsocketURLDemo,
+	}
+)
+
+// Custom returns an environment with other endpoints, e.g. a proxy or a fake
+// server. Without a websocketURL the environment has no websocket, and
+// connecting the websocket of a client fails with ErrNoWebsocketURL.
+func Custom(baseURL *url.URL, websocketURL *url.URL) Environment {
+	return Environment{
+		Name:         "custom",
+		BaseURL:      baseURL,
+		WebsocketURL: websocketURL,
+	}
+}
+
+// environmentOf returns the known environment that baseURL belongs to.
+func environmentOf(baseURL *url.URL) (Environment, bool) {
+	for _, env := range []Environment{Production, Demo} {
+		if baseURL != nil && baseURL.Host == env.BaseURL.Host {
+			return env, true
+		}
+	}
+	return Environment{}, false
+}
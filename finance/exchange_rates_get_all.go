@@ -1,6 +1,8 @@
 package finance
 
 import (
+	"context"
+
 	"github.com/omniboost/go-mews/json"
 )
 
@@ -10,18 +12,19 @@ const (
 
 // Returns configuration of the enterprise and the client.
 func (s *Service) ExchangeRatesGetAll(requestBody *ExchangeRatesGetAllRequest) (*ExchangeRatesGetAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.ExchangeRatesGetAllContext(requestBody.GetContext(), requestBody)
+}
 
+// ExchangeRatesGetAllContext is ExchangeRatesGetAll with a context and per-call options.
+func (s *Service) ExchangeRatesGetAllContext(ctx context.Context, requestBody *ExchangeRatesGetAllRequest, opts ...json.RequestOption) (*ExchangeRatesGetAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointExchangeRatesGetAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &ExchangeRatesGetAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -5,13 +5,14 @@ go 1.25.3
 require (
 	github.com/cydev/zero v0.0.0-20160322155811-4a4535dd56e7
 	github.com/gorilla/websocket v1.5.3
+	github.com/omniboost/go-httperr v0.0.0-20251103155253-030b17131c87
 	github.com/tim-online/go-errors v0.0.0-20170728152248-6b7d9120d8ce
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
-	github.com/omniboost/go-httperr v0.0.0-20251103155253-030b17131c87 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/stretchr/testify v1.6.1 // indirect
 )
@@ -1,27 +1,48 @@
 package identitydocuments
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/json"
 )
 
 const (
 	endpointAll = "identityDocuments/getAll"
+
+	// MaxCustomerIDs is the number of customers a request can filter on.
+	MaxCustomerIDs = 100
 )
 
 // List all outlets
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options. More than
+// MaxCustomerIDs customers are split into chunks, whose pages are all fetched
+// and merged into one response without a cursor.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	if len(requestBody.CustomerIDs) > MaxCustomerIDs {
+		return json.FetchChunks(ctx, requestBody.CustomerIDs, MaxCustomerIDs, func(ctx context.Context, ids []string) (*AllResponse, error) {
+			chunk := *requestBody
+			chunk.CustomerIDs = ids
+			chunk.Limitation.Cursor = ""
+			chunk.SetContext(ctx)
+			return json.MergePages(&chunk, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -30,11 +51,21 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all identity documents, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[IdentityDocument, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []IdentityDocument { return r.IdentityDocuments }, opts...)
+}
+
 type AllResponse struct {
 	IdentityDocuments IdentityDocuments `json:"IdentityDocuments"`
 	Cursor            string            `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *APIService) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -45,6 +76,18 @@ type AllRequest struct {
 	Limitation  json.Limitation `json:"Limitation,omitempty"`
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks that 1 to 100 customers are requested.
+func (r *AllRequest) Validate() error {
+	v := &json.Validation{}
+	v.Required("CustomerIds", len(r.CustomerIDs) > 0)
+	v.MaxItems("CustomerIds", len(r.CustomerIDs), MaxCustomerIDs)
+	return v.Err()
+}
+
 type IdentityDocuments []IdentityDocument
 
 type IdentityDocument struct {
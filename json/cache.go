@@ -0,0 +1,286 @@
+package json
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultCacheTTLs are the endpoints of reference data that rarely
+	// changes, with the time their responses are cached.
+	DefaultCacheTTLs = map[string]time.Duration{
+		"configuration/get":           time.Hour,
+		"countries/getAll":            24 * time.Hour,
+		"accountingCategories/getAll": time.Hour,
+		"services/getAll":             time.Hour,
+		"rates/getAll":                time.Hour,
+		"ageCategories/getAll":        time.Hour,
+		"businesssegments/getAll":     time.Hour,
+		"counters/getAll":             time.Hour,
+		"resourceCategories/getAll":   time.Hour,
+	}
+)
+
+// CacheStore stores cached responses. Keys start with the endpoint followed by
+// a colon, e.g. "countries/getAll:3f2a...".
+type CacheStore interface {
+	// Get returns the value of key, false when it isn't stored or expired.
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Invalidate removes the values of all keys that start with prefix.
+	Invalidate(ctx context.Context, prefix string) error
+}
+
+// Cache is middleware that caches the responses of endpoints with a TTL.
+// Identical requests, including their tokens, share a response; concurrent
+// identical requests are sent once. Responses served from the cache have no
+// *http.Response.
+//
+//	client.Use(json.NewCache(json.NewMemoryCacheStore(), json.DefaultCacheTTLs).Middleware)
+type Cache struct {
+	store CacheStore
+
+	// Optional logger for failures of the store, which are otherwise
+	// ignored
+	Logger *slog.Logger
+
+	mu      sync.Mutex
+	ttls    map[string]time.Duration
+	flights map[string]*flight
+}
+
+// flight is a request that is being sent for all callers with the same key.
+type flight struct {
+	done chan struct{}
+	b    []byte
+	err  error
+}
+
+// NewCache returns a cache that stores responses of the endpoints in ttls in
+// store. Endpoints that aren't listed aren't cached.
+func NewCache(store CacheStore, ttls map[string]time.Duration) *Cache {
+	c := &Cache{
+		store:   store,
+		ttls:    map[string]time.Duration{},
+		flights: map[string]*flight{},
+	}
+	for endpoint, ttl := range ttls {
+		c.ttls[endpoint] = ttl
+	}
+	return c
+}
+
+// SetTTL changes the TTL of endpoint, 0 stops caching it.
+func (c *Cache) SetTTL(endpoint string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ttl <= 0 {
+		delete(c.ttls, endpoint)
+		return
+	}
+	c.ttls[endpoint] = ttl
+}
+
+func (c *Cache) ttl(endpoint string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ttls[endpoint]
+}
+
+// Invalidate removes the cached responses of endpoints, or of all endpoints
+// when none are given.
+func (c *Cache) Invalidate(ctx context.Context, endpoints ...string) error {
+	if len(endpoints) == 0 {
+		return c.store.Invalidate(ctx, "")
+	}
+	for _, endpoint := range endpoints {
+		if err := c.store.Invalidate(ctx, endpoint+":"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Middleware serves the responses of cached endpoints from the store. Use it
+// with Client.Use.
+func (c *Cache) Middleware(next Handler) Handler {
+	return func(call *Call) (*http.Response, error) {
+		ttl := c.ttl(call.Endpoint)
+		if ttl <= 0 || call.Response == nil {
+			return next(call)
+		}
+		switch call.Response.(type) {
+		case io.Writer, StreamDecoder:
+			return next(call)
+		}
+
+		ctx := call.Request.Context()
+		key, err := cacheKey(call)
+		if err != nil {
+			return nil, err
+		}
+
+		// a failing store is a miss, the call is sent
+		if !OptionsFromContext(ctx).NoCache {
+			b, ok, err := c.store.Get(ctx, key)
+			if err != nil {
+				c.logError(ctx, "cache: get failed", call.Endpoint, err)
+			}
+			if err == nil && ok {
+				if err := json.Unmarshal(b, call.Response); err == nil {
+					return nil, nil
+				}
+			}
+		}
+
+		for {
+			c.mu.Lock()
+			f, ok := c.flights[key]
+			if !ok {
+				break
+			}
+			c.mu.Unlock()
+
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// the context of the caller that sent the request ended, which
+			// says nothing about this call: send it again, maybe as leader
+			if errors.Is(f.err, context.Canceled) || errors.Is(f.err, context.DeadlineExceeded) {
+				continue
+			}
+			if f.err != nil {
+				return nil, f.err
+			}
+			if f.b == nil {
+				// the response couldn't be shared
+				continue
+			}
+			return nil, json.Unmarshal(f.b, call.Response)
+		}
+		f := &flight{done: make(chan struct{})}
+		c.flights[key] = f
+		c.mu.Unlock()
+
+		// only the errors of the call itself are returned, a response that
+		// can't be stored is still returned and shared
+		httpResp, err := next(call)
+		f.err = err
+		if err == nil {
+			b, merr := json.Marshal(call.Response)
+			if merr != nil {
+				c.logError(ctx, "cache: encoding the response failed", call.Endpoint, merr)
+			} else if serr := c.store.Set(ctx, key, b, ttl); serr != nil {
+				c.logError(ctx, "cache: set failed", call.Endpoint, serr)
+			}
+			f.b = b
+		}
+
+		c.mu.Lock()
+		delete(c.flights, key)
+		c.mu.Unlock()
+		close(f.done)
+
+		return httpResp, err
+	}
+}
+
+func (c *Cache) logError(ctx context.Context, msg string, endpoint string, err error) {
+	if c.Logger == nil {
+		return
+	}
+	c.Logger.LogAttrs(context.WithoutCancel(ctx), slog.LevelWarn, msg,
+		slog.String("endpoint", endpoint),
+		slog.String("error", err.Error()),
+	)
+}
+
+// cacheKey returns the key of the endpoint and request body of call.
+func cacheKey(call *Call) (string, error) {
+	h := sha256.New()
+	if call.Request.GetBody != nil {
+		body, err := call.Request.GetBody()
+		if err != nil {
+			return "", err
+		}
+		defer body.Close()
+		if _, err := io.Copy(h, body); err != nil {
+			return "", err
+		}
+	}
+	return call.Endpoint + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// MemoryCacheStore is an in-memory CacheStore.
+type MemoryCacheStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+	now     func() time.Time
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{
+		entries: map[string]memoryCacheEntry{},
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !s.now().Before(e.expires) {
+		delete(s.entries, key)
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (s *MemoryCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// drop expired entries so the store doesn't grow forever
+	now := s.now()
+	for k, e := range s.entries {
+		if !now.Before(e.expires) {
+			delete(s.entries, k)
+		}
+	}
+
+	s.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryCacheStore) Invalidate(ctx context.Context, prefix string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for k := range s.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(s.entries, k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCacheTestClient(t *testing.T, handler http.HandlerFunc) (*Client, *Cache, *MemoryCacheStore) {
+	store := NewMemoryCacheStore()
+	cache := NewCache(store, DefaultCacheTTLs)
+
+	c := newServerClient(t, handler)
+	c.Use(cache.Middleware)
+	return c, cache, store
+}
+
+func TestCache(t *testing.T) {
+	calls := &atomic.Int32{}
+	c, cache, store := newCacheTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+
+	for i := 0; i < 3; i++ {
+		if err := testDo(c, "countries/getAll", context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("made %d calls, expected 1", n)
+	}
+
+	// endpoints without a TTL aren't cached
+	testDo(c, "customers/getAll", context.Background())
+	testDo(c, "customers/getAll", context.Background())
+	if n := calls.Load(); n != 3 {
+		t.Errorf("made %d calls, expected 3", n)
+	}
+
+	ctx := WithOptions(context.Background(), WithNoCache())
+	testDo(c, "countries/getAll", ctx)
+	if n := calls.Load(); n != 4 {
+		t.Errorf("made %d calls, expected 4", n)
+	}
+
+	cache.Invalidate(context.Background(), "countries/getAll")
+	testDo(c, "countries/getAll", context.Background())
+	if n := calls.Load(); n != 5 {
+		t.Errorf("made %d calls, expected 5", n)
+	}
+
+	store.now = func() time.Time { return time.Now().Add(25 * time.Hour) }
+	testDo(c, "countries/getAll", context.Background())
+	if n := calls.Load(); n != 6 {
+		t.Errorf("made %d calls after the TTL, expected 6", n)
+	}
+}
+
+func TestCacheDeduplicates(t *testing.T) {
+	calls := &atomic.Int32{}
+	release := make(chan struct{})
+	c, _, _ := newCacheTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		<-release
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := testDo(c, "configuration/get", context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// give the calls time to join the first one
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("made %d calls, expected 1", n)
+	}
+}
+
+func TestCacheLeaderCanceled(t *testing.T) {
+	calls := &atomic.Int32{}
+	release := make(chan struct{})
+	c, _, _ := newCacheTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+	c.RetryPolicy = nil
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leaderErr := make(chan error, 1)
+	go func() {
+		leaderErr <- testDo(c, "configuration/get", ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := testDo(c, "configuration/get", context.Background()); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	// let the calls join the first one before it is canceled
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-leaderErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, expected the leader to be canceled", err)
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 2 {
+		t.Errorf("made %d calls, expected 2", n)
+	}
+}
+
+// failingCacheStore fails every operation, like an external store that is down.
+type failingCacheStore struct{}
+
+func (failingCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	return nil, false, errors.New("store is down")
+}
+
+func (failingCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("store is down")
+}
+
+func (failingCacheStore) Invalidate(ctx context.Context, prefix string) error {
+	return errors.New("store is down")
+}
+
+func TestCacheStoreFailures(t *testing.T) {
+	calls := &atomic.Int32{}
+	c, _, _ := newCacheTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+	logs := &strings.Builder{}
+	cache := NewCache(failingCacheStore{}, DefaultCacheTTLs)
+	cache.Logger = slog.New(slog.NewTextHandler(logs, nil))
+	c.middleware = nil
+	c.Use(cache.Middleware)
+
+	for i := 0; i < 2; i++ {
+		if err := testDo(c, "countries/getAll", context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("made %d calls, expected every call to be sent", n)
+	}
+	if !strings.Contains(logs.String(), "cache: set failed") {
+		t.Errorf("logs = %q, expected the failures to be logged", logs)
+	}
+}
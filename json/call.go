@@ -0,0 +1,96 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Call sends requestBody to an endpoint the library doesn't wrap yet, e.g.
+// "tasks/getAll", and decodes the response into response like Do. requestBody
+// can be any struct or map that encodes to a JSON object, or a
+// json.RawMessage; nil sends an empty object. The tokens, client name,
+// language and culture are added to it like to the requests of the services,
+// and the call goes through the same middleware, rate limiter and retries.
+func (c *Client) Call(ctx context.Context, endpoint string, requestBody interface{}, response interface{}, opts ...RequestOption) (*http.Response, error) {
+	apiURL, err := c.GetApiURL(strings.TrimPrefix(endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := c.NewRequestWithContext(ctx, apiURL, &callBody{value: requestBody}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(httpReq, response)
+}
+
+// CallRaw is Call with a raw JSON request and response body.
+func (c *Client) CallRaw(ctx context.Context, endpoint string, requestBody json.RawMessage, opts ...RequestOption) (json.RawMessage, error) {
+	var body interface{}
+	if len(bytes.TrimSpace(requestBody)) > 0 {
+		body = requestBody
+	}
+
+	response := json.RawMessage{}
+	_, err := c.Call(ctx, endpoint, body, &response, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// callBody is the request body of Call: the body of the caller with the
+// fields of BaseRequest added.
+type callBody struct {
+	BaseRequest
+	value interface{}
+}
+
+// Validate runs the Validate method of the body of the caller, if it has one.
+func (b *callBody) Validate() error {
+	if v, ok := b.value.(Validator); ok {
+		return v.Validate()
+	}
+	return nil
+}
+
+func (b *callBody) MarshalJSON() ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	if b.value != nil {
+		body, err := json.Marshal(b.value)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(body))
+		dec.UseNumber()
+		if err := dec.Decode(&fields); err != nil || fields == nil {
+			return nil, fmt.Errorf("request body must be a JSON object: %s", body)
+		}
+	}
+
+	base, err := json.Marshal(b.BaseRequest)
+	if err != nil {
+		return nil, err
+	}
+	baseFields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(base, &baseFields); err != nil {
+		return nil, err
+	}
+
+	// the tokens, language and culture of the client replace the ones of the
+	// caller, like they do for the requests of the services
+	for key, value := range baseFields {
+		for k := range fields {
+			if strings.EqualFold(k, key) {
+				delete(fields, k)
+			}
+		}
+		fields[key] = value
+	}
+	return json.Marshal(fields)
+}
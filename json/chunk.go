@@ -0,0 +1,194 @@
+package json
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+var (
+	// DefaultChunkConcurrency is the number of chunks of a call that are
+	// fetched at the same time, see WithChunkConcurrency.
+	DefaultChunkConcurrency = 4
+)
+
+// Chunks splits items into chunks of at most size items.
+func Chunks[T any](items []T, size int) [][]T {
+	if size <= 0 {
+		return [][]T{items}
+	}
+
+	chunks := make([][]T, 0, (len(items)+size-1)/size)
+	for len(items) > size {
+		chunks = append(chunks, items[:size:size])
+		items = items[size:]
+	}
+	return append(chunks, items)
+}
+
+// FetchChunks calls fetch for every chunk of at most size ids and merges the
+// responses with MergeResponses, in the order of the chunks. The chunks are
+// fetched concurrently, as many at a time as the ChunkConcurrency of the call
+// allows. The first error cancels the chunks that are still being fetched.
+func FetchChunks[Resp any](ctx context.Context, ids []string, size int, fetch func(ctx context.Context, ids []string) (*Resp, error), opts ...RequestOption) (*Resp, error) {
+	concurrency := OptionsFromContext(WithOptions(ctx, opts...)).ChunkConcurrency
+	if concurrency <= 0 {
+		concurrency = DefaultChunkConcurrency
+	}
+	return fetchParts(ctx, Chunks(ids, size), concurrency, fetch)
+}
+
+// fetchParts calls fetch for every part, at most concurrency at a time, and
+// merges the responses in the order of the parts.
+func fetchParts[P any, Resp any](ctx context.Context, parts []P, concurrency int, fetch func(ctx context.Context, part P) (*Resp, error)) (*Resp, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	responses := make([]*Resp, len(parts))
+	sem := make(chan struct{}, concurrency)
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+	)
+	for i, part := range parts {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			resp, err := fetch(ctx, part)
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = err
+					cancel()
+				})
+				return
+			}
+			responses[i] = resp
+		}()
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	merged := new(Resp)
+	for _, resp := range responses {
+		MergeResponses(merged, resp)
+	}
+	return merged, nil
+}
+
+// MergePages fetches every page of req and merges them with MergeResponses
+// into one response without a cursor.
+func MergePages[Req PagedRequest, Resp PagedResponse](req Req, fetch func(Req) (Resp, error), opts ...PageOption) (Resp, error) {
+	var merged Resp
+	for resp, err := range Pages(req, fetch, opts...) {
+		if err != nil {
+			var zero Resp
+			return zero, err
+		}
+		if reflect.ValueOf(merged).IsNil() {
+			merged = resp
+			continue
+		}
+		MergeResponses(merged, resp)
+	}
+	if !reflect.ValueOf(merged).IsNil() {
+		clearCursor(reflect.ValueOf(merged).Elem())
+	}
+	return merged, nil
+}
+
+// MergeResponses appends the slices of the response src points to to the
+// ones of dst, e.g. the Reservations and Customers of two
+// reservations.AllResponse. Items with an ID that dst already has are
+// skipped, so related entities returned for several chunks appear once. Other
+// fields of dst are only set when they are empty.
+func MergeResponses(dst interface{}, src interface{}) {
+	d := reflect.ValueOf(dst)
+	s := reflect.ValueOf(src)
+	if d.Kind() != reflect.Pointer || s.Kind() != reflect.Pointer || s.IsNil() || d.Type() != s.Type() {
+		return
+	}
+	d, s = d.Elem(), s.Elem()
+	if d.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < d.NumField(); i++ {
+		if !d.Type().Field(i).IsExported() {
+			continue
+		}
+		df, sf := d.Field(i), s.Field(i)
+		if df.Kind() == reflect.Slice {
+			df.Set(appendNew(df, sf))
+			continue
+		}
+		if df.IsZero() {
+			df.Set(sf)
+		}
+	}
+}
+
+// appendNew appends the items of src to dst that have no ID in dst.
+func appendNew(dst reflect.Value, src reflect.Value) reflect.Value {
+	seen := map[string]bool{}
+	for i := 0; i < dst.Len(); i++ {
+		if id := idOf(dst.Index(i)); id != "" {
+			seen[id] = true
+		}
+	}
+
+	for i := 0; i < src.Len(); i++ {
+		item := src.Index(i)
+		id := idOf(item)
+		if id != "" && seen[id] {
+			continue
+		}
+		seen[id] = true
+		dst = reflect.Append(dst, item)
+	}
+	return dst
+}
+
+// idOf returns the ID field of a struct, or "" when it has none.
+func idOf(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("ID")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
+
+func clearCursor(v reflect.Value) {
+	if v.Kind() != reflect.Struct {
+		return
+	}
+	if f := v.FieldByName("Cursor"); f.IsValid() && f.Kind() == reflect.String && f.CanSet() {
+		f.SetString("")
+	}
+}
@@ -0,0 +1,124 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type testChunkItem struct {
+	ID string
+}
+
+type testChunkResponse struct {
+	Items     []testChunkItem
+	Customers []testChunkItem
+	Cursor    string
+}
+
+func testIDs(n int) []string {
+	ids := make([]string, n)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+	return ids
+}
+
+func TestChunks(t *testing.T) {
+	chunks := Chunks(testIDs(2500), 1000)
+	if len(chunks) != 3 {
+		t.Fatalf("len(chunks) = %d, expected 3", len(chunks))
+	}
+	for i, n := range []int{1000, 1000, 500} {
+		if len(chunks[i]) != n {
+			t.Errorf("len(chunks[%d]) = %d, expected %d", i, len(chunks[i]), n)
+		}
+	}
+	if chunks[1][0] != "1000" {
+		t.Errorf("chunks[1][0] = %s, expected 1000", chunks[1][0])
+	}
+}
+
+func TestFetchChunksMerges(t *testing.T) {
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	release := make(chan struct{})
+	var releaseOnce sync.Once
+
+	fetch := func(ctx context.Context, ids []string) (*testChunkResponse, error) {
+		mu.Lock()
+		running++
+		maxRunning = max(maxRunning, running)
+		if running == 2 {
+			releaseOnce.Do(func() { close(release) })
+		}
+		mu.Unlock()
+		<-release
+
+		resp := &testChunkResponse{}
+		for _, id := range ids {
+			resp.Items = append(resp.Items, testChunkItem{ID: id})
+		}
+		// every chunk returns the same related customer
+		resp.Customers = []testChunkItem{{ID: "customer"}}
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return resp, nil
+	}
+
+	resp, err := FetchChunks(context.Background(), testIDs(5000), 1000, fetch, WithChunkConcurrency(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxRunning != 2 {
+		t.Errorf("%d chunks fetched at the same time, expected 2", maxRunning)
+	}
+	if len(resp.Items) != 5000 {
+		t.Fatalf("len(Items) = %d, expected 5000", len(resp.Items))
+	}
+	for i, item := range resp.Items {
+		if item.ID != strconv.Itoa(i) {
+			t.Fatalf("Items[%d] = %s, expected %d", i, item.ID, i)
+		}
+	}
+	if len(resp.Customers) != 1 {
+		t.Errorf("len(Customers) = %d, expected the duplicates to be merged", len(resp.Customers))
+	}
+}
+
+func TestFetchChunksError(t *testing.T) {
+	errChunk := errors.New("chunk failed")
+	fetch := func(ctx context.Context, ids []string) (*testChunkResponse, error) {
+		if ids[0] == "1000" {
+			return nil, errChunk
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+
+	_, err := FetchChunks(context.Background(), testIDs(3000), 1000, fetch)
+	if !errors.Is(err, errChunk) {
+		t.Errorf("err = %v, expected %v", err, errChunk)
+	}
+}
+
+func TestMergePages(t *testing.T) {
+	calls := []Limitation{}
+	req := &testPagedRequest{}
+	resp, err := MergePages(req, testFetcher(25, &calls), WithPageSize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Items) != 25 {
+		t.Errorf("len(Items) = %d, expected 25", len(resp.Items))
+	}
+	if resp.Cursor != "" {
+		t.Errorf("Cursor = %q, expected none", resp.Cursor)
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"fmt"
 	"io"
 	"log"
+	"log/slog"
 	"net/http"
-	"net/http/httputil"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	httperr "github.com/omniboost/go-httperr"
@@ -23,11 +25,15 @@ var (
 
 	ErrNoAccessToken = errors.New("No access token specified")
 	ErrNoClientToken = errors.New("No client token specified")
-	ctxRetryAttempt  = ContextKey("retry-attempt")
+	ctxAccessToken   = ContextKey("access-token")
 )
 
 type ContextKey string
 
+// Client sends requests to the API. A Client is safe for concurrent use by
+// multiple goroutines once it is configured: set its fields before the first
+// request and pass settings that differ between calls as RequestOptions.
+// SetLanguageCode and SetCultureCode may be called at any time.
 type Client struct {
 	// HTTP client used to communicate with the DO API.
 	Client *http.Client
@@ -35,30 +41,82 @@ type Client struct {
 	// Base URL for API requests
 	BaseURL *url.URL
 
-	// Debugging flag
+	// Debugging flag, dumps requests and responses with the standard logger.
+	// Tokens and card data are redacted.
 	Debug bool
 
+	// Optional structured logger, every attempt of a request is logged
+	Logger *slog.Logger
+	// Also log the request and response bodies, redacted and truncated
+	LogBodies bool
+	// Number of bytes of a body that is logged, DefaultMaxLogBodySize if 0
+	MaxLogBodySize int
+
 	// Disallow unknown json fields
 	DisallowUnknownFields bool
+	// Decode responses with unknown json fields, but report the fields, see
+	// DriftReport. Ignored when DisallowUnknownFields is set.
+	ReportUnknownFields bool
+	// unknown fields found when ReportUnknownFields is set
+	drift driftRecorder
+
+	// Don't ask for compressed responses. By default the client accepts gzip
+	// and deflate and decodes the responses itself, see OnTransfer.
+	DisableCompression bool
+	// Gzip request bodies of at least this many bytes, 0 never compresses
+	// them
+	CompressRequestsOver int
+
+	// Send requests without validating them first, see Validate
+	SkipValidation bool
 
 	// User agent for client
 	UserAgent string
 
 	AccessToken string
 	ClientToken string
-
+	// Resolves the tokens of every request, nil uses AccessToken and
+	// ClientToken
+	TokenProvider TokenProvider
+	// The access token is a portfolio access token: writes must name the
+	// enterprise they are for
+	Portfolio bool
+	// Name and version of the integration, sent as Client of every request
+	ClientName string
+
+	// default language and culture, guarded by codesMu
+	codesMu      sync.RWMutex
 	languageCode string
 	cultureCode  string
 
 	// Optional function called after every successful request made to the DO APIs
 	onRequestCompleted RequestCompletionCallback
+	// Optional function called with the body sizes of every attempt
+	onTransfer TransferCallback
+
+	// Middleware wrapped around Do
+	middleware []Middleware
+
+	// Timeout of a single attempt, a deadline of the request context limits
+	// all attempts together
+	Timeout time.Duration
 
-	Timeout        time.Duration
+	// Decides which failed requests are sent again, nil disables retries
+	RetryPolicy RetryPolicy
+
+	// Deprecated: configure RetryPolicy instead. Without a RetryPolicy,
+	// setting RetryOnTimeout or MaxRetries retries with the backoff of
+	// DefaultRetryPolicy.
 	RetryOnTimeout bool
-	MaxRetries     int
+	// Deprecated: configure RetryPolicy instead.
+	MaxRetries int
+
+	// Optional client side rate limiter, consulted before every request
+	Limiter Limiter
 
-	// 429 - Too many requests handling
-	retryAfter *time.Time
+	// 429 - Too many requests handling when no Limiter is set
+	retryAfterMu sync.Mutex
+	retryAfter   *time.Time
 }
 
 // RequestCompletionCallback defines the type of the request callback function
@@ -91,71 +149,184 @@ func (c *Client) GetApiURL(path string) (*url.URL, error) {
 	return apiURL, nil
 }
 
-func cloneRequest(req *http.Request, ctx context.Context) (*http.Request, error) {
+// endpoint returns the path of the request relative to the BaseURL, e.g.
+// "customers/getAll".
+func (c *Client) endpoint(req *http.Request) string {
+	if c.BaseURL == nil {
+		return req.URL.Path
+	}
+	return strings.TrimPrefix(req.URL.Path, c.BaseURL.Path)
+}
+
+func accessTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(ctxAccessToken).(string)
+	return token
+}
+
+// rewindRequest returns a copy of a request that has already been sent, with
+// a fresh body.
+func rewindRequest(req *http.Request, ctx context.Context) (*http.Request, error) {
 	newReq := req.Clone(ctx)
-	if req.Body != nil && req.Body != http.NoBody {
-		// If the request has a body, we need to read it and set it again
-		body, err := io.ReadAll(req.Body)
+	if req.GetBody != nil {
+		body, err := req.GetBody()
 		if err != nil {
 			return nil, err
 		}
-		req.Body = io.NopCloser(bytes.NewReader(body))
-		newReq.Body = io.NopCloser(bytes.NewReader(body))
+		newReq.Body = body
 	}
 	return newReq, nil
 }
 
-// Do sends an API request and returns the API response. The API response is XML decoded and stored in the value
+// Do sends an API request and returns the API response. The API response is JSON decoded and stored in the value
 // pointed to by v, or returned as an error if an API error has occurred. If v implements the io.Writer interface,
-// the raw response will be written to v, without attempting to decode it.
+// the raw response will be written to v, without attempting to decode it. If v implements StreamDecoder, it
+// decodes the body itself.
+//
+// The call passes through the middleware added with Use. Failed attempts are sent again for as long as the
+// RetryPolicy allows it.
 func (c *Client) Do(req *http.Request, response interface{}) (*http.Response, error) {
-	if c.Debug == true {
-		dump, _ := httputil.DumpRequestOut(req, true)
-		log.Println(string(dump))
+	call := &Call{
+		Endpoint:    c.endpoint(req),
+		Request:     req,
+		RequestBody: requestBodyFromContext(req.Context()),
+		Response:    response,
+		client:      c,
 	}
+	return c.handler()(call)
+}
 
-	// Wait until "Retry-After" time has passed
-	c.sleepUntilRetryAfter()
+// scopeRequestBody adds the enterprise scope of the options to an encoded
+// request body, and checks that writes with a portfolio access token name
+// their enterprise.
+func (c *Client) scopeRequestBody(body []byte, endpoint string, options RequestOptions) ([]byte, error) {
+	if ids := options.enterpriseIDs(); len(ids) > 0 {
+		b, err := setEnterprise(body, endpoint, ids)
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
 
-	allowRetry := c.RetryOnTimeout
-	originalContext := req.Context()
-	retryAttempt, ok := originalContext.Value(ctxRetryAttempt).(int)
-	if !ok {
-		retryAttempt = 0
+	if c.Portfolio && !IsIdempotent(endpoint) {
+		ok, err := hasEnterprise(body)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: %w", endpoint, ErrNoEnterprise)
+		}
 	}
-	// if the request context doesn't have a deadline set, and we have a default deadline, set a timeout
-	if _, ok := originalContext.Deadline(); !ok && c.Timeout > 0 {
-		ctx, cancel := context.WithTimeout(originalContext, c.Timeout)
-		defer cancel()
-		req = req.WithContext(ctx)
-		// only allow retry if we haven't reached the maximum number of retries
-		allowRetry = retryAttempt < c.MaxRetries
-	} else {
-		// if the request context already has a deadline set,
-		// retry on timeout is useless, since the deadline will already be in the past
-		allowRetry = false
+	return body, nil
+}
+
+// retryPolicy returns the RetryPolicy of the client, or one built from the
+// deprecated RetryOnTimeout and MaxRetries when only those are set.
+func (c *Client) retryPolicy() RetryPolicy {
+	if c.RetryPolicy != nil || (!c.RetryOnTimeout && c.MaxRetries <= 0) {
+		return c.RetryPolicy
 	}
 
-	var originalReq *http.Request
-	if allowRetry {
-		var err error
-		originalReq, err = cloneRequest(req, context.WithValue(originalContext, ctxRetryAttempt, retryAttempt+1))
+	policy := DefaultRetryPolicy()
+	if c.MaxRetries > 0 {
+		policy.MaxRetries = c.MaxRetries
+	}
+	return policy
+}
+
+// send is the innermost Handler: it sends the request of call, retrying failed
+// attempts.
+func (c *Client) send(call *Call) (*http.Response, error) {
+	req := call.Request
+	response := call.Response
+	ctx := req.Context()
+	endpoint := call.Endpoint
+	options := OptionsFromContext(ctx)
+	policy := options.retryPolicy(c.retryPolicy())
+	attempt := Attempt{
+		Endpoint:   endpoint,
+		Idempotent: IsIdempotent(endpoint) || retryNonIdempotent(ctx),
+	}
+
+	for {
+		attempt.Number++
+		httpResp, err := c.do(req, response, attempt.Number)
+		if err == nil || policy == nil || ctx.Err() != nil {
+			return httpResp, err
+		}
+
+		attempt.Response = httpResp
+		attempt.Err = err
+		attempt.RetryAfter = retryAfterFromError(err)
+		delay, retry := policy.Retry(attempt)
+		if !retry {
+			return httpResp, err
+		}
+
+		if c.Debug || options.Debug {
+			log.Printf("Request to %s failed (%v), retrying in %s...", endpoint, err, delay)
+		}
+
+		if serr := sleep(ctx, delay); serr != nil {
+			return httpResp, err
+		}
+
+		req, err = rewindRequest(req, ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to clone request: %w", err)
 		}
 	}
+}
 
-	httpResp, err := c.Client.Do(req)
-	if err != nil {
-		if errors.Is(err, context.DeadlineExceeded) && allowRetry {
-			// if the request timed out, retry it
-			if c.Debug == true {
-				log.Println("Request timed out, retrying...")
-			}
-			time.Sleep(500 * time.Millisecond)
-			return c.Do(originalReq, response)
+// do sends a single attempt of an API request and logs it.
+func (c *Client) do(req *http.Request, response interface{}, attempt int) (*http.Response, error) {
+	info := &attemptInfo{start: time.Now()}
+	httpResp, err := c.doAttempt(req, response, info)
+	c.logAttempt(req, attempt, info, err)
+	if c.onTransfer != nil && info.resp != nil {
+		c.onTransfer(req, c.transfer(req, info))
+	}
+	return httpResp, err
+}
+
+func (c *Client) doAttempt(req *http.Request, response interface{}, info *attemptInfo) (*http.Response, error) {
+	options := OptionsFromContext(req.Context())
+	debug := c.Debug || options.Debug
+	if debug {
+		log.Println(dumpRequest(req))
+	}
+
+	accessToken := accessTokenFromContext(req.Context())
+	if c.Limiter != nil {
+		// Wait until the rate limiter allows the request
+		err := c.Limiter.Wait(req.Context(), accessToken, c.endpoint(req))
+		if err != nil {
+			return nil, err
 		}
+	} else {
+		// Wait until "Retry-After" time has passed
+		c.sleepUntilRetryAfter()
+	}
+
+	// limit the attempt, a deadline of the request context still applies
+	// to all attempts together
+	timeout := options.Timeout
+	if timeout == 0 {
+		timeout = c.Timeout
+	}
+	if timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
 
+	sent, err := c.compressRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	info.sent = sent
+
+	httpResp, err := c.Client.Do(sent)
+	if err != nil {
 		// wrap error in http error so we can handle it properly
 		statusCode := 0
 		if httpResp != nil {
@@ -163,6 +334,15 @@ func (c *Client) Do(req *http.Request, response interface{}) (*http.Response, er
 		}
 		return nil, &httperr.Error{StatusCode: statusCode, Err: err}
 	}
+	info.resp = httpResp
+
+	info.wire = &byteCounter{ReadCloser: httpResp.Body}
+	info.encoding, err = decompressResponse(httpResp, info.wire)
+	if err != nil {
+		httpResp.Body.Close()
+		return nil, err
+	}
+
 	if c.onRequestCompleted != nil {
 		c.onRequestCompleted(req, httpResp)
 	}
@@ -174,17 +354,32 @@ func (c *Client) Do(req *http.Request, response interface{}) (*http.Response, er
 		}
 	}()
 
-	if c.Debug == true {
-		dump, _ := httputil.DumpResponse(httpResp, true)
-		log.Println(string(dump))
+	if debug {
+		log.Println(dumpResponse(httpResp))
 	}
 
-	// Handle '429 - Too many requests' responses
+	// count the response bytes and keep the start of the body for logging
+	info.body = &bodyRecorder{ReadCloser: httpResp.Body}
+	if c.Logger != nil && c.LogBodies {
+		info.body.max = c.maxLogBodySize()
+	}
+	httpResp.Body = info.body
+
+	// Handle '429 - Too many requests' responses: pause every request using
+	// this token, not just this one
 	if httpResp.StatusCode == http.StatusTooManyRequests {
-		c.SetRetryAfterByResponse(httpResp)
-		c.sleepUntilRetryAfter()
-		c.SetRetryAfter(nil)
-		return c.Do(originalReq, response)
+		now := time.Now()
+		wait := parseRetryAfter(httpResp.Header.Get("Retry-After"), now)
+		if wait <= 0 {
+			wait = time.Second
+		}
+		retryAfter := now.Add(wait)
+
+		if c.Limiter != nil {
+			c.Limiter.Throttle(accessToken, retryAfter)
+		} else {
+			c.SetRetryAfter(&retryAfter)
+		}
 	}
 
 	// check if the response isn't an error
@@ -209,7 +404,16 @@ func (c *Client) Do(req *http.Request, response interface{}) (*http.Response, er
 	if c.DisallowUnknownFields {
 		dec.DisallowUnknownFields()
 	}
-	err = dec.Decode(response)
+	if s, ok := response.(StreamDecoder); ok {
+		endpoint := c.endpoint(req)
+		err = s.DecodeStream(dec, func(path string, data []byte, v interface{}) error {
+			return c.decode(endpoint, path, data, v)
+		})
+	} else if c.ReportUnknownFields && !c.DisallowUnknownFields {
+		err = c.decodeReportingDrift(c.endpoint(req), httpResp.Body, response)
+	} else {
+		err = dec.Decode(response)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -219,28 +423,74 @@ func (c *Client) Do(req *http.Request, response interface{}) (*http.Response, er
 // NewRequest creates an API request. A relative URL can be provided in urlStr, which will be resolved to the
 // BaseURL of the Client. Relative URLS should always be specified without a preceding slash. If specified, the
 // value pointed to by body is XML encoded and included in as the request body.
+//
+// The request uses the context set on requestBody with SetContext.
 func (c *Client) NewRequest(apiURL *url.URL, requestBody interface{}) (*http.Request, error) {
-	buf := new(bytes.Buffer)
 	ctx := context.Background()
+	if s, ok := requestBody.(RequestBody); ok {
+		ctx = s.GetContext()
+	}
+	return c.NewRequestWithContext(ctx, apiURL, requestBody)
+}
+
+// NewRequestWithContext creates an API request that uses ctx. The options
+// carried by ctx and opts apply to this request only. The tokens, language and
+// culture are set on requestBody, so a request body can't be shared by calls
+// that run at the same time.
+func (c *Client) NewRequestWithContext(ctx context.Context, apiURL *url.URL, requestBody interface{}, opts ...RequestOption) (*http.Request, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if len(opts) > 0 {
+		ctx = WithOptions(ctx, opts...)
+	}
+	options := OptionsFromContext(ctx)
+
+	tokens, err := c.Tokens(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !c.SkipValidation {
+		if err := Validate(requestBody); err != nil {
+			return nil, err
+		}
+	}
+
+	buf := new(bytes.Buffer)
 	if requestBody != nil {
 		if s, ok := requestBody.(RequestBody); ok {
-			s.SetAccessToken(c.AccessToken)
-			s.SetClientToken(c.ClientToken)
-			if c.languageCode != "" {
-				s.SetLanguageCode(c.languageCode)
+			c.codesMu.RLock()
+			languageCode, cultureCode := c.languageCode, c.cultureCode
+			c.codesMu.RUnlock()
+
+			s.SetAccessToken(tokens.AccessToken)
+			s.SetClientToken(tokens.ClientToken)
+			if n, ok := requestBody.(clientNamer); ok && c.ClientName != "" {
+				n.SetClient(c.ClientName)
+			}
+			if code := firstNonEmpty(options.LanguageCode, languageCode); code != "" {
+				s.SetLanguageCode(code)
 			}
-			if c.cultureCode != "" {
-				s.SetCultureCode(c.cultureCode)
+			if code := firstNonEmpty(options.CultureCode, cultureCode); code != "" {
+				s.SetCultureCode(code)
 			}
-			ctx = s.GetContext()
 		}
+		ctx = context.WithValue(ctx, ctxRequestBody, requestBody)
 
 		err := json.NewEncoder(buf).Encode(requestBody)
 		if err != nil {
 			return nil, err
 		}
+
+		b, err := c.scopeRequestBody(buf.Bytes(), c.endpoint(&http.Request{URL: apiURL}), options)
+		if err != nil {
+			return nil, err
+		}
+		buf = bytes.NewBuffer(b)
 	}
 
+	ctx = context.WithValue(ctx, ctxAccessToken, tokens.AccessToken)
 	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL.String(), buf)
 	if err != nil {
 		return nil, err
@@ -249,35 +499,71 @@ func (c *Client) NewRequest(apiURL *url.URL, requestBody interface{}) (*http.Req
 	httpReq.Header.Add("Content-Type", fmt.Sprintf("%s; charset=%s", mediaType, charset))
 	httpReq.Header.Add("Accept", mediaType)
 	httpReq.Header.Add("User-Agent", c.UserAgent)
+	// ask for compression explicitly, so the client sees the compressed
+	// sizes instead of the transport decoding the body
+	if c.DisableCompression {
+		httpReq.Header.Add("Accept-Encoding", "identity")
+	} else {
+		httpReq.Header.Add("Accept-Encoding", acceptEncoding)
+	}
 	return httpReq, nil
 }
 
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
 // OnRequestCompleted sets the DO API request completion callback
 func (c *Client) OnRequestCompleted(rc RequestCompletionCallback) {
 	c.onRequestCompleted = rc
 }
 
-func (c *Client) CheckTokens() error {
-	if c.AccessToken == "" {
-		return ErrNoAccessToken
+// Tokens resolves the tokens of a call with the TokenProvider. Failures are
+// returned as a *TokenError.
+func (c *Client) Tokens(ctx context.Context) (Tokens, error) {
+	provider := c.TokenProvider
+	if provider == nil {
+		provider = StaticTokens{AccessToken: c.AccessToken, ClientToken: c.ClientToken}
 	}
+	return ResolveTokens(ctx, provider)
+}
 
-	if c.ClientToken == "" {
-		return ErrNoClientToken
+// CheckTokens reports a missing token of a client without a TokenProvider.
+//
+// Deprecated: the TokenProvider checks the tokens of every request when it is
+// created.
+func (c *Client) CheckTokens() error {
+	if c.TokenProvider != nil {
+		return nil
 	}
-
-	return nil
+	return Tokens{AccessToken: c.AccessToken, ClientToken: c.ClientToken}.check()
 }
 
+// SetLanguageCode sets the default language of all requests. Use
+// WithLanguageCode for a single call.
 func (c *Client) SetLanguageCode(code string) {
+	c.codesMu.Lock()
+	defer c.codesMu.Unlock()
 	c.languageCode = code
 }
 
+// SetCultureCode sets the default culture of all requests. Use
+// WithCultureCode for a single call.
 func (c *Client) SetCultureCode(code string) {
+	c.codesMu.Lock()
+	defer c.codesMu.Unlock()
 	c.cultureCode = code
 }
 
 func (c *Client) SetRetryAfter(retryAfter *time.Time) {
+	c.retryAfterMu.Lock()
+	defer c.retryAfterMu.Unlock()
+
 	// Set the "Retry-After" time
 	c.retryAfter = retryAfter
 }
@@ -291,10 +577,14 @@ func (c *Client) SetRetryAfterByResponse(req *http.Response) error {
 		return nil
 	}
 
-	// Parse the "Retry-After" header to a duration in seconds
-	retryAfterTime, err := time.Parse(time.RFC1123, retryAfter)
+	// Parse the "Retry-After" header, either a HTTP date or a number of seconds
+	retryAfterTime, err := http.ParseTime(retryAfter)
 	if err != nil {
-		return err
+		d := parseRetryAfter(retryAfter, time.Now())
+		if d == 0 {
+			return err
+		}
+		retryAfterTime = time.Now().Add(d)
 	}
 
 	// Set the "Retry-After" time
@@ -304,14 +594,18 @@ func (c *Client) SetRetryAfterByResponse(req *http.Response) error {
 }
 
 func (c *Client) sleepUntilRetryAfter() {
+	c.retryAfterMu.Lock()
+	retryAfter := c.retryAfter
+	c.retryAfterMu.Unlock()
+
 	// When the "Retry-After" time is not set, continue
-	if c.retryAfter == nil || c.retryAfter.IsZero() {
+	if retryAfter == nil || retryAfter.IsZero() {
 		return
 	}
 
 	// Calculate the duration to sleep
 	now := time.Now()
-	diff := c.retryAfter.Sub(now)
+	diff := retryAfter.Sub(now)
 	if diff <= 0 {
 		return
 	}
@@ -320,6 +614,11 @@ func (c *Client) sleepUntilRetryAfter() {
 	time.Sleep(diff)
 }
 
+// clientNamer is implemented by request bodies with a Client field.
+type clientNamer interface {
+	SetClient(string)
+}
+
 type RequestBody interface {
 	SetAccessToken(string)
 	SetClientToken(string)
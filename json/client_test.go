@@ -0,0 +1,169 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client pointed at a test server that fails the
+// first n requests with the given status code.
+func newTestClient(t *testing.T, n int32, statusCode int) (*Client, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	c := newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			w.WriteHeader(statusCode)
+			w.Write([]byte(`{"Message":"failed"}`))
+			return
+		}
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+	c.RetryPolicy = &BackoffRetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+	return c, calls
+}
+
+// newServerClient returns a client that sends its requests to a test server
+// serving handler.
+func newServerClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c := NewClient(server.Client(), "access", "client")
+	c.BaseURL, _ = url.Parse(server.URL + "/api/connector/v1/")
+	return c
+}
+
+func testDo(c *Client, endpoint string, ctx context.Context) error {
+	apiURL, err := c.GetApiURL(endpoint)
+	if err != nil {
+		return err
+	}
+
+	requestBody := &BaseRequest{}
+	requestBody.SetContext(ctx)
+	req, err := c.NewRequest(apiURL, requestBody)
+	if err != nil {
+		return err
+	}
+
+	responseBody := &struct{ Cursor string }{}
+	_, err = c.Do(req, responseBody)
+	if err == nil && responseBody.Cursor != "abc" {
+		return errors.New("response wasn't decoded")
+	}
+	return err
+}
+
+func TestDoRetriesIdempotent(t *testing.T) {
+	c, calls := newTestClient(t, 2, http.StatusServiceUnavailable)
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, expected 3", calls.Load())
+	}
+}
+
+func TestDoDoesNotRetryNonIdempotent(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusServiceUnavailable)
+
+	err := testDo(c, "payments/addExternal", context.Background())
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("err = %v, expected a server error", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, expected 1", calls.Load())
+	}
+}
+
+func TestDoRetriesNonIdempotentOptIn(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusServiceUnavailable)
+
+	err := testDo(c, "payments/addExternal", WithRetryNonIdempotent(context.Background()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, expected 2", calls.Load())
+	}
+}
+
+func TestDoDoesNotRetryValidation(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusBadRequest)
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("err = %v, expected a validation error", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("calls = %d, expected 1", calls.Load())
+	}
+}
+
+func TestDoMaxRetries(t *testing.T) {
+	c, calls := newTestClient(t, 10, http.StatusBadGateway)
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("err = %v, expected a server error", err)
+	}
+	if calls.Load() != 4 {
+		t.Errorf("calls = %d, expected 4", calls.Load())
+	}
+}
+
+func TestDoDeprecatedMaxRetries(t *testing.T) {
+	c, calls := newTestClient(t, 10, http.StatusBadGateway)
+	c.RetryPolicy = nil
+	c.MaxRetries = 1
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if !errors.Is(err, ErrServer) {
+		t.Errorf("err = %v, expected a server error", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, expected 2", calls.Load())
+	}
+}
+
+func TestDoThrottlesLimiter(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusTooManyRequests)
+	limiter := NewRateLimiter(DefaultRateLimit)
+	c.Limiter = limiter
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("calls = %d, expected 2", calls.Load())
+	}
+	if limiter.State("access").ThrottledUntil.IsZero() {
+		t.Error("expected the 429 to throttle the limiter")
+	}
+}
+
+func TestIsIdempotent(t *testing.T) {
+	tests := map[string]bool{
+		"customers/getAll":               true,
+		"reservations/getAll/2023-06-06": true,
+		"bills/getPdf":                   true,
+		"configuration/get":              true,
+		"payments/addExternal":           false,
+		"reservations/add":               false,
+		"customers/update":               false,
+	}
+
+	for endpoint, expected := range tests {
+		if got := IsIdempotent(endpoint); got != expected {
+			t.Errorf("IsIdempotent(%q) = %v, expected %v", endpoint, got, expected)
+		}
+	}
+}
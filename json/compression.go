@@ -0,0 +1,167 @@
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	// Content codings the client accepts, unless DisableCompression is set
+	acceptEncoding = "gzip, deflate"
+)
+
+// Transfer is the size of the bodies of one attempt of a request, as sent over
+// the wire and decoded. The sizes are equal for bodies that weren't
+// compressed.
+type Transfer struct {
+	// Endpoint relative to the BaseURL, e.g. "accountingItems/getAll"
+	Endpoint          string
+	RequestBytes      int64
+	RequestWireBytes  int64
+	RequestEncoding   string
+	ResponseBytes     int64
+	ResponseWireBytes int64
+	ResponseEncoding  string
+}
+
+// TransferCallback is called with the sizes of every attempt, see
+// Client.OnTransfer.
+type TransferCallback func(*http.Request, Transfer)
+
+// OnTransfer sets the callback that receives the sizes of the bodies of every
+// attempt, to measure the savings of compression.
+func (c *Client) OnTransfer(tc TransferCallback) {
+	c.onTransfer = tc
+}
+
+// compressRequest returns a copy of req with a gzip compressed body when the
+// body has at least c.CompressRequestsOver bytes, or req itself.
+func (c *Client) compressRequest(req *http.Request) (*http.Request, error) {
+	if c.CompressRequestsOver <= 0 || req.ContentLength < int64(c.CompressRequestsOver) || req.GetBody == nil {
+		return req, nil
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	buf := &bytes.Buffer{}
+	zw := gzip.NewWriter(buf)
+	if _, err := io.Copy(zw, body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	b := buf.Bytes()
+	compressed := req.Clone(req.Context())
+	compressed.Body = io.NopCloser(bytes.NewReader(b))
+	compressed.ContentLength = int64(len(b))
+	compressed.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	compressed.Header.Set("Content-Encoding", "gzip")
+	return compressed, nil
+}
+
+// decompressResponse replaces the body of a compressed response, read through
+// wire, with the decoded body. It returns the content coding of the response,
+// "" when it wasn't compressed.
+func decompressResponse(resp *http.Response, wire io.ReadCloser) (string, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+
+	var r io.Reader
+	var err error
+	switch encoding {
+	case "", "identity":
+		resp.Body = wire
+		return "", nil
+	case "gzip", "x-gzip":
+		r, err = gzip.NewReader(wire)
+	case "deflate":
+		r, err = newDeflateReader(wire)
+	default:
+		return "", fmt.Errorf("unsupported Content-Encoding %q", encoding)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s response: %w", encoding, err)
+	}
+
+	resp.Body = &decompressedBody{Reader: r, body: wire}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return encoding, nil
+}
+
+// newDeflateReader reads a deflate coded body. The coding is a zlib stream,
+// but some servers send raw deflate data.
+func newDeflateReader(r io.Reader) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
+
+// decompressedBody closes the body it decompresses.
+type decompressedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+func (b *decompressedBody) Close() error {
+	if c, ok := b.Reader.(io.Closer); ok {
+		c.Close()
+	}
+	return b.body.Close()
+}
+
+// byteCounter counts the bytes read from a body.
+type byteCounter struct {
+	io.ReadCloser
+	n int64
+}
+
+func (c *byteCounter) Read(p []byte) (int, error) {
+	n, err := c.ReadCloser.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// transfer returns the sizes of the bodies of an attempt.
+func (c *Client) transfer(req *http.Request, info *attemptInfo) Transfer {
+	t := Transfer{
+		Endpoint:         c.endpoint(req),
+		RequestBytes:     req.ContentLength,
+		RequestWireBytes: req.ContentLength,
+	}
+	if info.sent != nil && info.sent.Header.Get("Content-Encoding") != "" {
+		t.RequestWireBytes = info.sent.ContentLength
+		t.RequestEncoding = info.sent.Header.Get("Content-Encoding")
+	}
+	if info.body != nil {
+		t.ResponseBytes = info.body.n
+		t.ResponseWireBytes = info.body.n
+	}
+	if info.wire != nil && info.encoding != "" {
+		t.ResponseWireBytes = info.wire.n
+		t.ResponseEncoding = info.encoding
+	}
+	return t
+}
@@ -0,0 +1,132 @@
+package json
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newCompressionClient returns a client for a server that answers with a large
+// response in the encoding the request accepts, and records the headers and
+// decoded body of the last request.
+func newCompressionClient(t *testing.T, encoding string) (*Client, *http.Header, *[]byte) {
+	header := &http.Header{}
+	body := &[]byte{}
+	c := newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		*header = r.Header.Clone()
+
+		var rb io.Reader = r.Body
+		if r.Header.Get("Content-Encoding") == "gzip" {
+			zr, err := gzip.NewReader(r.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			rb = zr
+		}
+		*body, _ = io.ReadAll(rb)
+
+		resp := `{"Cursor":"` + strings.Repeat("abc", 1000) + `"}`
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+			w.Write([]byte(resp))
+			return
+		}
+
+		w.Header().Set("Content-Encoding", encoding)
+		var zw io.WriteCloser
+		switch encoding {
+		case "gzip":
+			zw = gzip.NewWriter(w)
+		case "deflate":
+			zw = zlib.NewWriter(w)
+		}
+		zw.Write([]byte(resp))
+		zw.Close()
+	})
+	return c, header, body
+}
+
+type testCompressionRequest struct {
+	BaseRequest
+	Notes string
+}
+
+func testCompressionDo(t *testing.T, c *Client, requestBody interface{}) (Transfer, string) {
+	transfer := Transfer{}
+	c.OnTransfer(func(req *http.Request, tr Transfer) {
+		transfer = tr
+	})
+
+	apiURL, _ := c.GetApiURL("accountingItems/getAll")
+	req, err := c.NewRequestWithContext(context.Background(), apiURL, requestBody)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &struct{ Cursor string }{}
+	if _, err := c.Do(req, resp); err != nil {
+		t.Fatal(err)
+	}
+	return transfer, resp.Cursor
+}
+
+func TestCompressedResponses(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			c, header, _ := newCompressionClient(t, encoding)
+			transfer, cursor := testCompressionDo(t, c, &BaseRequest{})
+
+			if got := header.Get("Accept-Encoding"); got != "gzip, deflate" {
+				t.Errorf("Accept-Encoding = %q", got)
+			}
+			if len(cursor) != 3000 {
+				t.Errorf("len(Cursor) = %d, expected the decoded response", len(cursor))
+			}
+			if transfer.ResponseEncoding != encoding {
+				t.Errorf("ResponseEncoding = %q, expected %q", transfer.ResponseEncoding, encoding)
+			}
+			if transfer.ResponseBytes != 3013 || transfer.ResponseWireBytes >= transfer.ResponseBytes {
+				t.Errorf("transfer = %+v, expected fewer bytes on the wire", transfer)
+			}
+		})
+	}
+}
+
+func TestDisableCompression(t *testing.T) {
+	c, header, _ := newCompressionClient(t, "gzip")
+	c.DisableCompression = true
+	transfer, _ := testCompressionDo(t, c, &BaseRequest{})
+
+	if got := header.Get("Accept-Encoding"); got != "identity" {
+		t.Errorf("Accept-Encoding = %q", got)
+	}
+	if transfer.ResponseEncoding != "" || transfer.ResponseWireBytes != transfer.ResponseBytes {
+		t.Errorf("transfer = %+v, expected an uncompressed response", transfer)
+	}
+}
+
+func TestCompressRequests(t *testing.T) {
+	c, header, body := newCompressionClient(t, "gzip")
+	c.CompressRequestsOver = 1000
+
+	transfer, _ := testCompressionDo(t, c, &BaseRequest{})
+	if header.Get("Content-Encoding") != "" || transfer.RequestEncoding != "" {
+		t.Errorf("small request was compressed: %+v", transfer)
+	}
+
+	transfer, _ = testCompressionDo(t, c, &testCompressionRequest{Notes: strings.Repeat("note ", 1000)})
+	if header.Get("Content-Encoding") != "gzip" {
+		t.Errorf("Content-Encoding = %q, expected gzip", header.Get("Content-Encoding"))
+	}
+	req := testCompressionRequest{}
+	if err := json.Unmarshal(*body, &req); err != nil || len(req.Notes) != 5000 {
+		t.Errorf("server received %d bytes of notes (%v)", len(req.Notes), err)
+	}
+	if transfer.RequestEncoding != "gzip" || transfer.RequestWireBytes >= transfer.RequestBytes {
+		t.Errorf("transfer = %+v, expected fewer bytes on the wire", transfer)
+	}
+}
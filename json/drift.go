@@ -0,0 +1,255 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Drift is a field of a response the library doesn't know, found when
+// ReportUnknownFields is set. It means the type needs a new field.
+type Drift struct {
+	// Endpoint relative to the BaseURL, e.g. "reservations/getAll"
+	Endpoint string
+	// Go type that doesn't have the field, e.g. "reservations.Reservation"
+	Type string
+	// JSON path of the field in the response, e.g. "Reservations[].Purpose"
+	Path      string
+	Count     int
+	FirstSeen time.Time
+	LastSeen  time.Time
+}
+
+func (d Drift) String() string {
+	return d.Endpoint + ": " + d.Path
+}
+
+// DriftCallback is called the first time an unknown field is found, see
+// Client.OnDrift.
+type DriftCallback func(Drift)
+
+// OnDrift sets the callback that is called the first time an unknown field of
+// an endpoint is found. It is only called when ReportUnknownFields is set.
+func (c *Client) OnDrift(dc DriftCallback) {
+	c.drift.mu.Lock()
+	defer c.drift.mu.Unlock()
+	c.drift.callback = dc
+}
+
+// DriftReport returns the unknown fields found since the client was created,
+// sorted by endpoint and path.
+func (c *Client) DriftReport() []Drift {
+	return c.drift.report()
+}
+
+// decode decodes data, the JSON value at path of a response of endpoint, into
+// v. Unknown fields fail it when DisallowUnknownFields is set, and are
+// recorded when ReportUnknownFields is.
+func (c *Client) decode(endpoint string, path string, data []byte, v interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if c.DisallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if c.ReportUnknownFields && !c.DisallowUnknownFields {
+		c.drift.record(endpoint, path, data, v)
+	}
+	return nil
+}
+
+// decodeReportingDrift decodes body into response and records the fields of
+// body that response doesn't have.
+func (c *Client) decodeReportingDrift(endpoint string, body io.Reader, response interface{}) error {
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return err
+	}
+	c.drift.record(endpoint, "", b, response)
+	return nil
+}
+
+// driftRecorder collects the unknown fields of responses.
+type driftRecorder struct {
+	mu       sync.Mutex
+	fields   map[string]*Drift
+	callback DriftCallback
+}
+
+func (r *driftRecorder) report() []Drift {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := make([]Drift, 0, len(r.fields))
+	for _, d := range r.fields {
+		report = append(report, *d)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		if report[i].Endpoint != report[j].Endpoint {
+			return report[i].Endpoint < report[j].Endpoint
+		}
+		return report[i].Path < report[j].Path
+	})
+	return report
+}
+
+// record adds the unknown fields of body, the JSON value at path of a response
+// decoded into response, to the report.
+func (r *driftRecorder) record(endpoint string, path string, body []byte, response interface{}) {
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return
+	}
+
+	found := map[string]Drift{}
+	unknownFields(v, reflect.TypeOf(response), path, func(t reflect.Type, path string) {
+		found[path] = Drift{Endpoint: endpoint, Type: t.String(), Path: path}
+	})
+	if len(found) == 0 {
+		return
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	if r.fields == nil {
+		r.fields = map[string]*Drift{}
+	}
+	added := []Drift{}
+	for path, d := range found {
+		key := endpoint + ":" + path
+		if existing, ok := r.fields[key]; ok {
+			existing.Count++
+			existing.LastSeen = now
+			continue
+		}
+		d.Count, d.FirstSeen, d.LastSeen = 1, now, now
+		r.fields[key] = &d
+		added = append(added, d)
+	}
+	callback := r.callback
+	r.mu.Unlock()
+
+	if callback != nil {
+		for _, d := range added {
+			callback(d)
+		}
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// unknownFields calls unknown for every key of the decoded JSON v that type t
+// has no field for. Types that decode themselves aren't checked.
+func unknownFields(v interface{}, t reflect.Type, path string, unknown func(t reflect.Type, path string)) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Implements(unmarshalerType) || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch v := v.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			for key, value := range v {
+				f, ok := fields.lookup(key)
+				if !ok {
+					unknown(t, joinPath(path, key))
+					continue
+				}
+				unknownFields(value, f, joinPath(path, key), unknown)
+			}
+		case reflect.Map:
+			for _, value := range v {
+				unknownFields(value, t.Elem(), path+"{}", unknown)
+			}
+		}
+	case []interface{}:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return
+		}
+		for _, value := range v {
+			unknownFields(value, t.Elem(), path+"[]", unknown)
+		}
+	}
+}
+
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// structFields are the JSON names of the fields of a struct with their types.
+type structFields map[string]reflect.Type
+
+// lookup finds the field of a key like encoding/json does: by exact name,
+// then without regard to case.
+func (f structFields) lookup(key string) (reflect.Type, bool) {
+	if t, ok := f[key]; ok {
+		return t, true
+	}
+	for name, t := range f {
+		if strings.EqualFold(name, key) {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+var fieldsCache sync.Map
+
+// jsonFields returns the JSON fields of struct type t, including the fields of
+// embedded structs.
+func jsonFields(t reflect.Type) structFields {
+	if f, ok := fieldsCache.Load(t); ok {
+		return f.(structFields)
+	}
+
+	fields := structFields{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for n, et := range jsonFields(ft) {
+				if _, ok := fields[n]; !ok {
+					fields[n] = et
+				}
+			}
+			continue
+		}
+		if !sf.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		fields[name] = sf.Type
+	}
+
+	fieldsCache.Store(t, fields)
+	return fields
+}
@@ -0,0 +1,129 @@
+package json
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+type testDriftItem struct {
+	ID    string `json:"Id"`
+	Notes string
+}
+
+type testDriftResponse struct {
+	Items  []testDriftItem `json:"Items"`
+	Byid   map[string]testDriftItem
+	Date   Date
+	Cursor string
+}
+
+func newDriftClient(t *testing.T, body string) *Client {
+	return newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	})
+}
+
+func testDriftDo(c *Client) (*testDriftResponse, error) {
+	apiURL, _ := c.GetApiURL("reservations/getAll")
+	req, err := c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{})
+	if err != nil {
+		return nil, err
+	}
+	resp := &testDriftResponse{}
+	_, err = c.Do(req, resp)
+	return resp, err
+}
+
+func TestReportUnknownFields(t *testing.T) {
+	c := newDriftClient(t, `{
+		"Items": [{"Id": "1", "notes": "a", "Purpose": "Leisure"}, {"Id": "2", "Purpose": "Business"}],
+		"Byid": {"1": {"Id": "1", "Channel": "x"}},
+		"Date": "2024-01-01",
+		"Cursor": "c",
+		"Total": 2
+	}`)
+	c.ReportUnknownFields = true
+
+	drift := []Drift{}
+	c.OnDrift(func(d Drift) {
+		drift = append(drift, d)
+	})
+
+	for i := 0; i < 2; i++ {
+		resp, err := testDriftDo(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Items) != 2 || resp.Cursor != "c" {
+			t.Fatalf("resp = %+v, expected the known fields to be decoded", resp)
+		}
+	}
+
+	report := c.DriftReport()
+	expected := []struct{ path, typ string }{
+		{"Byid{}.Channel", "json.testDriftItem"},
+		{"Items[].Purpose", "json.testDriftItem"},
+		{"Total", "json.testDriftResponse"},
+	}
+	if len(report) != len(expected) {
+		t.Fatalf("report = %v, expected %d fields", report, len(expected))
+	}
+	for i, e := range expected {
+		d := report[i]
+		if d.Endpoint != "reservations/getAll" || d.Path != e.path || d.Type != e.typ {
+			t.Errorf("report[%d] = %+v, expected %s of %s", i, d, e.path, e.typ)
+		}
+		if d.Count != 2 {
+			t.Errorf("%s: Count = %d, expected 2", d.Path, d.Count)
+		}
+	}
+	if len(drift) != 3 {
+		t.Errorf("OnDrift was called %d times, expected once per field", len(drift))
+	}
+}
+
+func TestDisallowUnknownFieldsWins(t *testing.T) {
+	c := newDriftClient(t, `{"Cursor": "c", "Total": 2}`)
+	c.ReportUnknownFields = true
+	c.DisallowUnknownFields = true
+
+	if _, err := testDriftDo(c); err == nil {
+		t.Error("expected an error for the unknown field")
+	}
+	if report := c.DriftReport(); len(report) != 0 {
+		t.Errorf("report = %v, expected none", report)
+	}
+}
+
+func TestStreamReportsUnknownFields(t *testing.T) {
+	c := newDriftClient(t, `{"Items": [{"Id": "1", "Purpose": "Leisure"}], "Cursor": "c", "Total": 1}`)
+	c.ReportUnknownFields = true
+
+	apiURL, _ := c.GetApiURL("reservations/getAll")
+	req, err := c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := &testDriftResponse{}
+	_, err = c.Do(req, NewStream("Items", resp, func(item testDriftItem) error {
+		return nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := c.DriftReport()
+	if len(report) != 2 || report[0].Path != "Items[].Purpose" || report[1].Path != "Total" {
+		t.Errorf("report = %v, expected Items[].Purpose and Total", report)
+	}
+
+	c.DisallowUnknownFields = true
+	req, _ = c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{})
+	_, err = c.Do(req, NewStream("Items", resp, func(item testDriftItem) error {
+		return nil
+	}))
+	if err == nil {
+		t.Error("expected an error for the unknown fields")
+	}
+}
@@ -0,0 +1,23 @@
+package json
+
+import "testing"
+
+func TestDurationString(t *testing.T) {
+	tests := map[string]Duration{
+		"P0D":     {},
+		"P1Y2M3D": {Years: 1, Months: 2, Days: 3},
+		"PT14H":   {Hours: 14},
+		"P1DT30M": {Days: 1, Minutes: 30},
+	}
+
+	for expected, d := range tests {
+		if got := d.String(); got != expected {
+			t.Errorf("%+v.String() = %q, expected %q", d, got, expected)
+		}
+
+		parsed, err := ParseISO8601(expected)
+		if err != nil || parsed != d {
+			t.Errorf("ParseISO8601(%q) = %+v, %v", expected, parsed, err)
+		}
+	}
+}
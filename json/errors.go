@@ -0,0 +1,104 @@
+package json
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+var (
+	// Sentinel errors matched by the typed API errors using errors.Is
+	ErrValidation   = errors.New("Request failed validation")
+	ErrInvalidToken = errors.New("Invalid access token or client token")
+	ErrForbidden    = errors.New("Forbidden or insufficient permissions")
+	ErrThrottled    = errors.New("Request was throttled")
+	ErrServer       = errors.New("Server error")
+)
+
+// ValidationError is returned on a 400 response: the request itself is
+// invalid and sending it again won't help.
+type ValidationError struct {
+	*ErrorResponse
+}
+
+func (e *ValidationError) Unwrap() error        { return e.ErrorResponse }
+func (e *ValidationError) Is(target error) bool { return target == ErrValidation }
+
+// InvalidTokenError is returned on a 401 response: the access token or client
+// token is invalid or has been revoked.
+type InvalidTokenError struct {
+	*ErrorResponse
+}
+
+func (e *InvalidTokenError) Unwrap() error        { return e.ErrorResponse }
+func (e *InvalidTokenError) Is(target error) bool { return target == ErrInvalidToken }
+
+// ForbiddenError is returned on a 403 response: the token is valid but isn't
+// allowed to perform the operation.
+type ForbiddenError struct {
+	*ErrorResponse
+}
+
+func (e *ForbiddenError) Unwrap() error        { return e.ErrorResponse }
+func (e *ForbiddenError) Is(target error) bool { return target == ErrForbidden }
+
+// ThrottledError is returned on a 408 or 429 response.
+type ThrottledError struct {
+	*ErrorResponse
+
+	// Time to wait before sending the next request, zero when unknown
+	RetryAfter time.Duration
+}
+
+func (e *ThrottledError) Unwrap() error        { return e.ErrorResponse }
+func (e *ThrottledError) Is(target error) bool { return target == ErrThrottled }
+
+// ServerError is returned on a 5xx response.
+type ServerError struct {
+	*ErrorResponse
+}
+
+func (e *ServerError) Unwrap() error        { return e.ErrorResponse }
+func (e *ServerError) Is(target error) bool { return target == ErrServer }
+
+// newAPIError wraps the error response in the typed error matching its status
+// code. Status codes without a typed error return the error response itself.
+func newAPIError(r *ErrorResponse) error {
+	switch code := r.StatusCode(); {
+	case code == http.StatusBadRequest:
+		return &ValidationError{r}
+	case code == http.StatusUnauthorized:
+		return &InvalidTokenError{r}
+	case code == http.StatusForbidden:
+		return &ForbiddenError{r}
+	case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+		return &ThrottledError{ErrorResponse: r, RetryAfter: parseRetryAfter(r.Response.Header.Get("Retry-After"), time.Now())}
+	case code >= 500 && code <= 599:
+		return &ServerError{r}
+	}
+	return r
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of
+// seconds or a HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+
+	t, err := http.ParseTime(value)
+	if err != nil {
+		return 0
+	}
+
+	d := t.Sub(now)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
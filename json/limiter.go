@@ -0,0 +1,197 @@
+package json
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+var (
+	// DefaultRateLimit keeps a single access token below the Connector API
+	// limit of 200 requests per 30 seconds: a full burst plus the refill of one
+	// interval never exceeds 200 requests.
+	DefaultRateLimit = RateLimit{
+		Requests: 180,
+		Interval: 30 * time.Second,
+		Burst:    20,
+	}
+)
+
+// Limiter is consulted by the client before every request is sent.
+type Limiter interface {
+	// Wait blocks until a request to endpoint may be sent with token, or the
+	// context is done.
+	Wait(ctx context.Context, token string, endpoint string) error
+	// Throttle blocks all requests with token until the given time. It is
+	// called when the API responds with "429 - Too many requests".
+	Throttle(token string, until time.Time)
+}
+
+// RateLimit describes a token bucket: Burst requests can be sent at once,
+// after which the bucket refills with Requests per Interval.
+type RateLimit struct {
+	Requests int
+	Interval time.Duration
+	Burst    int
+}
+
+func (l RateLimit) rate() float64 {
+	if l.Interval <= 0 {
+		return 0
+	}
+	return float64(l.Requests) / l.Interval.Seconds()
+}
+
+// LimiterState is a snapshot of the bucket of a single token.
+type LimiterState struct {
+	// Available request cost, negative when requests are waiting
+	Tokens float64
+	// Maximum request cost that can be sent at once
+	Capacity float64
+	// Number of requests currently waiting for the bucket
+	Waiting int
+	// Set when the API responded with "429 - Too many requests"
+	ThrottledUntil time.Time
+}
+
+// RateLimiter is a concurrency safe token bucket limiter with a bucket per
+// access token.
+type RateLimiter struct {
+	limit RateLimit
+	// Cost per endpoint, endpoints that aren't listed cost 1
+	costs map[string]float64
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+
+	now func() time.Time
+}
+
+type bucket struct {
+	tokens         float64
+	last           time.Time
+	waiting        int
+	throttledUntil time.Time
+}
+
+func NewRateLimiter(limit RateLimit) *RateLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+
+	return &RateLimiter{
+		limit:   limit,
+		costs:   map[string]float64{},
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// SetCost sets the cost of a request to endpoint, e.g. "accountingItems/getAll".
+func (l *RateLimiter) SetCost(endpoint string, cost float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.costs[endpoint] = cost
+}
+
+func (l *RateLimiter) Cost(endpoint string) float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.cost(endpoint)
+}
+
+func (l *RateLimiter) cost(endpoint string) float64 {
+	if cost, ok := l.costs[endpoint]; ok {
+		return cost
+	}
+	return 1
+}
+
+// bucket returns the refilled bucket of token. l.mu must be held.
+func (l *RateLimiter) bucket(token string) *bucket {
+	now := l.now()
+	b, ok := l.buckets[token]
+	if !ok {
+		b = &bucket{tokens: float64(l.limit.Burst), last: now}
+		l.buckets[token] = b
+		return b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = min(b.tokens+elapsed*l.limit.rate(), float64(l.limit.Burst))
+		b.last = now
+	}
+	return b
+}
+
+// Wait reserves the cost of endpoint in the bucket of token and blocks until
+// the reservation can be used. Reservations are served in order, so waiting
+// requests can't be starved by newer ones.
+func (l *RateLimiter) Wait(ctx context.Context, token string, endpoint string) error {
+	l.mu.Lock()
+	cost := l.cost(endpoint)
+	b := l.bucket(token)
+	b.tokens -= cost
+
+	now := l.now()
+	var delay time.Duration
+	if b.tokens < 0 {
+		rate := l.limit.rate()
+		if rate > 0 {
+			delay = time.Duration(-b.tokens / rate * float64(time.Second))
+		}
+	}
+	if b.throttledUntil.After(now) {
+		delay = max(delay, b.throttledUntil.Sub(now))
+	}
+
+	if delay <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	b.waiting++
+	l.mu.Unlock()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		l.mu.Lock()
+		b.waiting--
+		l.mu.Unlock()
+		return nil
+	case <-ctx.Done():
+		// hand back the reservation
+		l.mu.Lock()
+		b.waiting--
+		b.tokens += cost
+		l.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+func (l *RateLimiter) Throttle(token string, until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(token)
+	if until.After(b.throttledUntil) {
+		b.throttledUntil = until
+	}
+}
+
+// State returns a snapshot of the bucket of token.
+func (l *RateLimiter) State(token string) LimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	b := l.bucket(token)
+	return LimiterState{
+		Tokens:         b.tokens,
+		Capacity:       float64(l.limit.Burst),
+		Waiting:        b.waiting,
+		ThrottledUntil: b.throttledUntil,
+	}
+}
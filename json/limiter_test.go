@@ -0,0 +1,81 @@
+package json
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Requests: 1, Interval: time.Hour, Burst: 3})
+	now := time.Now()
+	l.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx, "token", "customers/getAll"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the bucket is empty and refills once an hour
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "token", "customers/getAll"); err == nil {
+		t.Error("expected the fourth request to wait")
+	}
+
+	// the cancelled reservation is handed back
+	state := l.State("token")
+	if state.Tokens != 0 || state.Waiting != 0 {
+		t.Errorf("state = %+v, expected 0 tokens and no waiting requests", state)
+	}
+
+	// other tokens have their own bucket
+	if err := l.Wait(context.Background(), "other", "customers/getAll"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRateLimiterCost(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Requests: 1, Interval: time.Hour, Burst: 10})
+	now := time.Now()
+	l.now = func() time.Time { return now }
+	l.SetCost("accountingItems/getAll", 4)
+
+	if err := l.Wait(context.Background(), "token", "accountingItems/getAll"); err != nil {
+		t.Fatal(err)
+	}
+	if state := l.State("token"); state.Tokens != 6 {
+		t.Errorf("Tokens = %v, expected 6", state.Tokens)
+	}
+}
+
+func TestRateLimiterThrottle(t *testing.T) {
+	l := NewRateLimiter(DefaultRateLimit)
+	l.Throttle("token", time.Now().Add(time.Hour))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx, "token", "customers/getAll"); err == nil {
+		t.Error("expected a throttled token to wait")
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	l := NewRateLimiter(RateLimit{Requests: 1000, Interval: time.Second, Burst: 10})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := l.Wait(context.Background(), "token", "customers/getAll"); err != nil {
+				t.Error(err)
+			}
+			l.State("token")
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,268 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMaxLogBodySize is the number of bytes of a body that is logged when
+	// body logging is enabled.
+	DefaultMaxLogBodySize = 4096
+
+	redacted = "[REDACTED]"
+)
+
+var (
+	// RedactedFields are the JSON fields (case insensitive) whose values are
+	// never logged: tokens and card data.
+	RedactedFields = []string{
+		"AccessToken",
+		"ClientToken",
+		"Password",
+		"CardNumber",
+		"CreditCardNumber",
+		"Cvv",
+		"Cvc",
+		"SecurityCode",
+		"StorageData",
+		"Expiration",
+	}
+
+	// RedactedHeaders are the HTTP headers whose values are never logged.
+	RedactedHeaders = []string{
+		"Authorization",
+		"Cookie",
+		"Set-Cookie",
+	}
+)
+
+// Redact returns a copy of a JSON body with the values of RedactedFields
+// replaced. Bodies that aren't valid JSON are redacted on a best effort basis.
+func Redact(body []byte) []byte {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return body
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return redactRegexp().ReplaceAll(body, []byte(`"$1":"`+redacted+`"`))
+	}
+
+	b, err := json.Marshal(redactValue(v))
+	if err != nil {
+		return []byte(redacted)
+	}
+	return b
+}
+
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range v {
+			if isRedactedField(k) {
+				if vv != nil {
+					v[k] = redacted
+				}
+				continue
+			}
+			v[k] = redactValue(vv)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = redactValue(v[i])
+		}
+	}
+	return v
+}
+
+func isRedactedField(name string) bool {
+	for _, f := range RedactedFields {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactRegexp() *regexp.Regexp {
+	names := make([]string, len(RedactedFields))
+	for i, f := range RedactedFields {
+		names[i] = regexp.QuoteMeta(f)
+	}
+	// also match values cut off by truncation
+	return regexp.MustCompile(`(?i)"(` + strings.Join(names, "|") + `)"\s*:\s*"[^"]*("|$)`)
+}
+
+// RedactHeader returns a copy of header with the values of RedactedHeaders
+// replaced.
+func RedactHeader(header http.Header) http.Header {
+	h := header.Clone()
+	for _, name := range RedactedHeaders {
+		if h.Get(name) != "" {
+			h.Set(name, redacted)
+		}
+	}
+	return h
+}
+
+// truncate limits a body to max bytes.
+func truncate(body []byte, max int) string {
+	if max <= 0 || len(body) <= max {
+		return string(body)
+	}
+	return string(body[:max]) + "...(truncated)"
+}
+
+// bodyRecorder counts the bytes read from a body and keeps the first max of
+// them for logging.
+type bodyRecorder struct {
+	io.ReadCloser
+	n   int64
+	max int
+	buf bytes.Buffer
+}
+
+func (r *bodyRecorder) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	if room := r.max - r.buf.Len(); room > 0 {
+		r.buf.Write(p[:min(n, room)])
+	}
+	return n, err
+}
+
+func (c *Client) maxLogBodySize() int {
+	if c.MaxLogBodySize > 0 {
+		return c.MaxLogBodySize
+	}
+	return DefaultMaxLogBodySize
+}
+
+// requestBodyForLog returns the redacted and truncated body of req.
+func (c *Client) requestBodyForLog(req *http.Request) string {
+	if req.GetBody == nil {
+		return ""
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+
+	b, _ := io.ReadAll(body)
+	return truncate(Redact(b), c.maxLogBodySize())
+}
+
+// dumpRequest dumps a request for debugging, with tokens and card data
+// redacted.
+func dumpRequest(req *http.Request) string {
+	r := req.Clone(req.Context())
+	r.Header = RedactHeader(req.Header)
+	r.Body = nil
+	dump, _ := httputil.DumpRequest(r, false)
+
+	if req.GetBody == nil {
+		return string(dump)
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return string(dump)
+	}
+	defer body.Close()
+	b, _ := io.ReadAll(body)
+	return string(dump) + string(Redact(b))
+}
+
+// dumpResponse dumps a response for debugging, with tokens and card data
+// redacted. The body of resp is replaced so it can still be read.
+func dumpResponse(resp *http.Response) string {
+	b, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+
+	r := *resp
+	r.Header = RedactHeader(resp.Header)
+	r.Body = nil
+	dump, _ := httputil.DumpResponse(&r, false)
+	return string(dump) + string(Redact(b))
+}
+
+type attemptInfo struct {
+	start time.Time
+	// request as sent, with a compressed body
+	sent *http.Request
+	resp *http.Response
+	// bytes of the response as read from the wire and decoded
+	wire     *byteCounter
+	body     *bodyRecorder
+	encoding string
+}
+
+// logAttempt logs a single attempt of a request.
+func (c *Client) logAttempt(req *http.Request, attempt int, info *attemptInfo, err error) {
+	if c.Logger == nil {
+		return
+	}
+
+	ctx := req.Context()
+	resp := info.resp
+	body := info.body
+	attrs := []slog.Attr{
+		slog.String("endpoint", c.endpoint(req)),
+		slog.Int("attempt", attempt),
+		slog.Duration("duration", time.Since(info.start)),
+	}
+	transfer := c.transfer(req, info)
+	attrs = append(attrs, slog.Int64("request_bytes", transfer.RequestBytes))
+	if transfer.RequestEncoding != "" {
+		attrs = append(attrs, slog.Int64("request_wire_bytes", transfer.RequestWireBytes))
+	}
+
+	if p, ok := requestBodyFromContext(ctx).(PagedRequest); ok {
+		if cursor := p.GetLimitation().Cursor; cursor != "" {
+			attrs = append(attrs, slog.String("cursor", cursor))
+		}
+	}
+
+	if resp != nil {
+		attrs = append(attrs, slog.Int("status", resp.StatusCode))
+		if id := resp.Header.Get("Request-Id"); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+	}
+	if body != nil {
+		attrs = append(attrs, slog.Int64("response_bytes", transfer.ResponseBytes))
+		if transfer.ResponseEncoding != "" {
+			attrs = append(attrs, slog.Int64("response_wire_bytes", transfer.ResponseWireBytes))
+		}
+	}
+
+	if c.LogBodies {
+		attrs = append(attrs, slog.String("request_body", c.requestBodyForLog(req)))
+		if body != nil {
+			attrs = append(attrs, slog.String("response_body", truncate(Redact(body.buf.Bytes()), c.maxLogBodySize())))
+		}
+	}
+
+	level := slog.LevelDebug
+	msg := "mews request"
+	if err != nil {
+		level = slog.LevelWarn
+		msg = "mews request failed"
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+
+	// the request context may already be done, don't let that drop the record
+	c.Logger.LogAttrs(context.WithoutCancel(ctx), level, msg, attrs...)
+}
@@ -0,0 +1,54 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	body := []byte(`{"AccessToken":"secret-access","ClientToken":"secret-client","CreditCard":{"Expiration":"12/2030","ObfuscatedNumber":"4111********1111"},"Limitation":{"Count":10}}`)
+	got := string(Redact(body))
+
+	for _, secret := range []string{"secret-access", "secret-client", "12/2030"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("redacted body %s contains %q", got, secret)
+		}
+	}
+	if !strings.Contains(got, `"Count":10`) || !strings.Contains(got, "4111********1111") {
+		t.Errorf("redacted body %s lost fields that aren't sensitive", got)
+	}
+}
+
+func TestRedactTruncated(t *testing.T) {
+	got := string(Redact([]byte(`{"Customers":[],"AccessToken":"secret-acc`)))
+	if strings.Contains(got, "secret") {
+		t.Errorf("redacted body %s contains the truncated token", got)
+	}
+}
+
+func TestLogAttempt(t *testing.T) {
+	c, _ := newTestClient(t, 0, http.StatusOK)
+
+	buf := &bytes.Buffer{}
+	c.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c.LogBodies = true
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := buf.String()
+	for _, expected := range []string{`"endpoint":"customers/getAll"`, `"status":200`, `"attempt":1`, `"response_bytes":16`, `"response_body":"{\"Cursor\":\"abc\"}"`} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("log %s doesn't contain %s", got, expected)
+		}
+	}
+	if strings.Contains(got, `"access"`) || strings.Contains(got, `"client"`) {
+		t.Errorf("log %s contains a token", got)
+	}
+}
@@ -0,0 +1,79 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+var (
+	ctxRequestBody = ContextKey("request-body")
+)
+
+// Call is a single call to an API endpoint as seen by middleware.
+type Call struct {
+	// Endpoint relative to the BaseURL, e.g. "customers/getAll"
+	Endpoint string
+	// HTTP request that will be sent
+	Request *http.Request
+	// Typed request body passed to NewRequest, e.g. *customers.AllRequest
+	RequestBody interface{}
+	// Value the response is decoded into, e.g. *customers.AllResponse
+	Response interface{}
+
+	client *Client
+}
+
+// EncodeRequestBody replaces the body of Request with the JSON encoding of
+// RequestBody, with the enterprise scope of the call added like NewRequest
+// does. Middleware that modifies RequestBody must call it for the change to be
+// sent.
+func (c *Call) EncodeRequestBody() error {
+	buf := new(bytes.Buffer)
+	err := json.NewEncoder(buf).Encode(c.RequestBody)
+	if err != nil {
+		return err
+	}
+
+	b := buf.Bytes()
+	if c.client != nil {
+		b, err = c.client.scopeRequestBody(b, c.Endpoint, OptionsFromContext(c.Request.Context()))
+		if err != nil {
+			return err
+		}
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(b))
+	c.Request.ContentLength = int64(len(b))
+	c.Request.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(b)), nil
+	}
+	return nil
+}
+
+// Handler handles a call. The decoded response is stored in call.Response.
+type Handler func(call *Call) (*http.Response, error)
+
+// Middleware wraps a Handler. It can inspect or modify the call before passing
+// it on to next, inspect the response or error afterwards, or return without
+// calling next at all.
+type Middleware func(next Handler) Handler
+
+// Use appends middleware to the chain around Do. The first middleware added is
+// the outermost one. Use isn't safe to call while requests are in flight.
+func (c *Client) Use(middleware ...Middleware) {
+	c.middleware = append(c.middleware, middleware...)
+}
+
+func (c *Client) handler() Handler {
+	h := Handler(c.send)
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+func requestBodyFromContext(ctx context.Context) interface{} {
+	return ctx.Value(ctxRequestBody)
+}
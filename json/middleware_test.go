@@ -0,0 +1,159 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMiddlewareOrder(t *testing.T) {
+	c, _ := newTestClient(t, 0, http.StatusOK)
+
+	order := []string{}
+	for _, name := range []string{"first", "second"} {
+		c.Use(func(next Handler) Handler {
+			return func(call *Call) (*http.Response, error) {
+				order = append(order, name)
+				return next(call)
+			}
+		})
+	}
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(order, ",") != "first,second" {
+		t.Errorf("order = %v, expected first,second", order)
+	}
+}
+
+func TestMiddlewareSeesCall(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusBadRequest)
+	c.RetryPolicy = nil
+
+	var seen *Call
+	var seenErr error
+	c.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			resp, err := next(call)
+			seen = call
+			seenErr = err
+			return resp, err
+		}
+	})
+
+	testDo(c, "customers/getAll", context.Background())
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d, expected 1", calls.Load())
+	}
+	if seen.Endpoint != "customers/getAll" {
+		t.Errorf("Endpoint = %q", seen.Endpoint)
+	}
+	if _, ok := seen.RequestBody.(*BaseRequest); !ok {
+		t.Errorf("RequestBody = %T, expected *BaseRequest", seen.RequestBody)
+	}
+	if !errors.Is(seenErr, ErrValidation) {
+		t.Errorf("err = %v, expected a validation error", seenErr)
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+	c, calls := newTestClient(t, 0, http.StatusOK)
+	c.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			call.Response.(*struct{ Cursor string }).Cursor = "abc"
+			return nil, nil
+		}
+	})
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("calls = %d, expected the request not to be sent", calls.Load())
+	}
+}
+
+func TestMiddlewareModifiesRequestBody(t *testing.T) {
+	c, _ := newTestClient(t, 0, http.StatusOK)
+
+	var sent string
+	c.Client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		sent = string(b)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"Cursor":"abc"}`)),
+			Request:    req,
+		}, nil
+	})
+	c.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			call.RequestBody.(RequestBody).SetAccessToken("injected")
+			if err := call.EncodeRequestBody(); err != nil {
+				return nil, err
+			}
+			return next(call)
+		}
+	})
+
+	err := testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sent, `"AccessToken":"injected"`) {
+		t.Errorf("body = %s, expected the injected access token", sent)
+	}
+}
+
+func TestMiddlewareKeepsEnterpriseScope(t *testing.T) {
+	c, _ := newTestClient(t, 0, http.StatusOK)
+	c.Portfolio = true
+
+	var sent string
+	c.Client.Transport = roundTripFunc(func(req *http.Request) (*http.Response, error) {
+		b, _ := io.ReadAll(req.Body)
+		sent = string(b)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"Cursor":"abc"}`)),
+			Request:    req,
+		}, nil
+	})
+	c.Use(func(next Handler) Handler {
+		return func(call *Call) (*http.Response, error) {
+			if err := call.EncodeRequestBody(); err != nil {
+				return nil, err
+			}
+			return next(call)
+		}
+	})
+
+	err := testDo(c, "customers/getAll", WithOptions(context.Background(), WithEnterpriseIDs("e1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sent, `"EnterpriseIds":["e1"]`) {
+		t.Errorf("body = %s, expected the enterprise scope", sent)
+	}
+
+	// writes keep their enterprise as well
+	err = testDo(c, "customers/add", WithOptions(context.Background(), WithEnterpriseID("e1")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(sent, `"EnterpriseId":"e1"`) {
+		t.Errorf("body = %s, expected the enterprise of the write", sent)
+	}
+}
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
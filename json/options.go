@@ -0,0 +1,225 @@
+package json
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	ctxRequestOptions = ContextKey("request-options")
+
+	ErrNoEnterprise        = errors.New("Writes with a portfolio access token require an EnterpriseId")
+	ErrMultipleEnterprises = errors.New("A write can only be scoped to one enterprise")
+)
+
+// RequestOptions are the settings of a single call. Zero values fall back to
+// the settings of the Client. Options are copied into the context of a call,
+// so they never change the Client or other calls.
+type RequestOptions struct {
+	// Timeout of a single attempt
+	Timeout time.Duration
+	// Language and culture of the response, e.g. en-US
+	LanguageCode string
+	CultureCode  string
+	// Enterprise of a portfolio token the call is scoped to
+	EnterpriseID string
+	// More enterprises a read is scoped to, on top of EnterpriseID
+	EnterpriseIDs []string
+	// Dump the requests and responses of the call, on top of Client.Debug
+	Debug bool
+	// Send the call even when its response is cached, see Cache
+	NoCache bool
+	// Number of chunks of an oversized ID filter fetched at the same time, see
+	// FetchChunks
+	ChunkConcurrency int
+	// Number of windows of a long interval filter fetched at the same time,
+	// see FetchWindows
+	WindowConcurrency int
+	// Retry policy of the call, nil disables retries. Only used when
+	// overrideRetryPolicy is set.
+	RetryPolicy         RetryPolicy
+	overrideRetryPolicy bool
+}
+
+// retryPolicy returns the retry policy of the call, or fallback if the call
+// doesn't have its own.
+func (o RequestOptions) retryPolicy(fallback RetryPolicy) RetryPolicy {
+	if o.overrideRetryPolicy {
+		return o.RetryPolicy
+	}
+	return fallback
+}
+
+type RequestOption func(*RequestOptions)
+
+// WithTimeout limits every attempt of a call to d.
+func WithTimeout(d time.Duration) RequestOption {
+	return func(o *RequestOptions) {
+		o.Timeout = d
+	}
+}
+
+func WithLanguageCode(code string) RequestOption {
+	return func(o *RequestOptions) {
+		o.LanguageCode = code
+	}
+}
+
+func WithCultureCode(code string) RequestOption {
+	return func(o *RequestOptions) {
+		o.CultureCode = code
+	}
+}
+
+// WithEnterpriseID scopes a call to one enterprise of a portfolio token. It
+// sets EnterpriseIds of reads and EnterpriseId of writes, unless the request
+// sets them itself.
+func WithEnterpriseID(id string) RequestOption {
+	return func(o *RequestOptions) {
+		o.EnterpriseID = id
+	}
+}
+
+// WithDebug dumps the requests and responses of a call with the standard
+// logger, like Client.Debug does for all calls.
+func WithDebug(debug bool) RequestOption {
+	return func(o *RequestOptions) {
+		o.Debug = debug
+	}
+}
+
+// WithNoCache sends a call to a cached endpoint to the API, and caches the
+// fresh response.
+func WithNoCache() RequestOption {
+	return func(o *RequestOptions) {
+		o.NoCache = true
+	}
+}
+
+// WithChunkConcurrency fetches n chunks of an oversized ID filter at the same
+// time instead of DefaultChunkConcurrency.
+func WithChunkConcurrency(n int) RequestOption {
+	return func(o *RequestOptions) {
+		o.ChunkConcurrency = n
+	}
+}
+
+// WithWindowConcurrency fetches n windows of a long interval filter at the
+// same time instead of one after the other.
+func WithWindowConcurrency(n int) RequestOption {
+	return func(o *RequestOptions) {
+		o.WindowConcurrency = n
+	}
+}
+
+// WithRetryPolicy replaces the retry policy of the client for a call. Passing
+// nil disables retries.
+func WithRetryPolicy(policy RetryPolicy) RequestOption {
+	return func(o *RequestOptions) {
+		o.RetryPolicy = policy
+		o.overrideRetryPolicy = true
+	}
+}
+
+// WithEnterpriseIDs scopes a read of a portfolio token to enterprises, see
+// WithEnterpriseID. Writes can only be scoped to one enterprise.
+func WithEnterpriseIDs(ids ...string) RequestOption {
+	return func(o *RequestOptions) {
+		o.EnterpriseIDs = append(o.EnterpriseIDs[:len(o.EnterpriseIDs):len(o.EnterpriseIDs)], ids...)
+	}
+}
+
+// enterpriseIDs returns the enterprises the call is scoped to.
+func (o RequestOptions) enterpriseIDs() []string {
+	ids := []string{}
+	for _, id := range append([]string{o.EnterpriseID}, o.EnterpriseIDs...) {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// WithOptions returns a copy of ctx that carries opts, on top of the options
+// ctx already carries. Options passed to a call take precedence over the ones
+// in its context.
+func WithOptions(ctx context.Context, opts ...RequestOption) context.Context {
+	o := OptionsFromContext(ctx)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return context.WithValue(ctx, ctxRequestOptions, o)
+}
+
+// OptionsFromContext returns the options carried by ctx.
+func OptionsFromContext(ctx context.Context) RequestOptions {
+	o, _ := ctx.Value(ctxRequestOptions).(RequestOptions)
+	return o
+}
+
+// setEnterprise adds the enterprise scope to a JSON request body: EnterpriseIds
+// for reads, EnterpriseId for writes. Reads are the idempotent endpoints, see
+// IsIdempotent. A scope the body already has is kept.
+func setEnterprise(body []byte, endpoint string, enterpriseIDs []string) ([]byte, error) {
+	fields := map[string]json.RawMessage{}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	if err := dec.Decode(&fields); err != nil {
+		return nil, err
+	}
+
+	key := "EnterpriseIds"
+	var value interface{} = enterpriseIDs
+	if !IsIdempotent(endpoint) {
+		if len(enterpriseIDs) > 1 {
+			return nil, fmt.Errorf("%s: %w", endpoint, ErrMultipleEnterprises)
+		}
+		key = "EnterpriseId"
+		value = enterpriseIDs[0]
+	}
+
+	for k, v := range fields {
+		if strings.EqualFold(k, key) && !isEmptyJSON(v) {
+			return body, nil
+		}
+		if strings.EqualFold(k, key) {
+			delete(fields, k)
+		}
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	fields[key] = b
+	return json.Marshal(fields)
+}
+
+// hasEnterprise reports whether a JSON request body of a write names the
+// enterprise it is for.
+func hasEnterprise(body []byte) (bool, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return false, err
+	}
+	for k, v := range fields {
+		if strings.EqualFold(k, "EnterpriseId") && !isEmptyJSON(v) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func isEmptyJSON(v json.RawMessage) bool {
+	switch string(bytes.TrimSpace(v)) {
+	case "", "null", `""`, "[]":
+		return true
+	}
+	return false
+}
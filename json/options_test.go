@@ -0,0 +1,200 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestNewRequestWithContextOptions(t *testing.T) {
+	c := NewClient(nil, "access", "client")
+	c.BaseURL, _ = url.Parse("https://api.mews.test/api/connector/v1/")
+	c.SetLanguageCode("en-US")
+
+	apiURL, _ := c.GetApiURL("customers/getAll")
+	ctx := WithOptions(context.Background(), WithLanguageCode("nl-NL"))
+	req, err := c.NewRequestWithContext(ctx, apiURL, &BaseRequest{}, WithCultureCode("nl-BE"), WithEnterpriseID("e1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, _ := io.ReadAll(req.Body)
+	for _, expected := range []string{`"LanguageCode":"nl-NL"`, `"CultureCode":"nl-BE"`, `"EnterpriseIds":["e1"]`} {
+		if !strings.Contains(string(b), expected) {
+			t.Errorf("body %s doesn't contain %s", b, expected)
+		}
+	}
+	if c.languageCode != "en-US" {
+		t.Errorf("client language code changed to %s", c.languageCode)
+	}
+}
+
+func TestSetEnterprise(t *testing.T) {
+	tests := []struct {
+		body     string
+		endpoint string
+		expected string
+	}{
+		{`{"A":1}`, "customers/getAll", `{"A":1,"EnterpriseIds":["e1"]}`},
+		{`{"A":1}`, "customers/add", `{"A":1,"EnterpriseId":"e1"}`},
+		{`{"EnterpriseIDs":null}`, "orderItems/getAll", `{"EnterpriseIds":["e1"]}`},
+		{`{"EnterpriseIds":["e2"]}`, "customers/getAll", `{"EnterpriseIds":["e2"]}`},
+		// registered as a read
+		{`{"A":1}`, "reservations/price", `{"A":1,"EnterpriseIds":["e1"]}`},
+	}
+	SetIdempotent("reservations/price", true)
+
+	for _, test := range tests {
+		got, err := setEnterprise([]byte(test.body), test.endpoint, []string{"e1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != test.expected {
+			t.Errorf("setEnterprise(%s, %s) = %s, expected %s", test.body, test.endpoint, got, test.expected)
+		}
+	}
+}
+
+func TestTimeoutIsPerAttempt(t *testing.T) {
+	calls := &atomic.Int32{}
+	c := newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			// outlast the timeout of the attempt
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+		w.Write([]byte(`{"Cursor":"abc"}`))
+	})
+	c.RetryPolicy = &BackoffRetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, RetryTimeouts: true}
+	c.Timeout = time.Minute
+
+	// the deadline of the call doesn't disable the timeout of an attempt
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = WithOptions(ctx, WithTimeout(50*time.Millisecond))
+
+	err := testDo(c, "customers/getAll", ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("made %d calls, expected 2", n)
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	c, calls := newTestClient(t, 1, http.StatusServiceUnavailable)
+
+	ctx := WithOptions(context.Background(), WithRetryPolicy(nil))
+	err := testDo(c, "customers/getAll", ctx)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if n := calls.Load(); n != 1 {
+		t.Errorf("made %d calls, expected 1", n)
+	}
+
+	// the retry policy of the client is untouched
+	err = testDo(c, "customers/getAll", context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientConcurrentOptions(t *testing.T) {
+	c := newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body := struct{ CultureCode string }{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// echo the culture of the request
+		json.NewEncoder(w).Encode(map[string]string{"Cursor": body.CultureCode})
+	})
+	c.SetCultureCode("en-US")
+	apiURL, _ := c.GetApiURL("customers/getAll")
+
+	cultures := []string{"nl-NL", "de-DE", "fr-FR", "cs-CZ"}
+	var wg sync.WaitGroup
+	for i := 0; i < 40; i++ {
+		wg.Add(1)
+		go func(culture string) {
+			defer wg.Done()
+
+			req, err := c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{}, WithCultureCode(culture), WithDebug(false))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp := &struct{ Cursor string }{}
+			if _, err := c.Do(req, resp); err != nil {
+				t.Error(err)
+				return
+			}
+			if resp.Cursor != culture {
+				t.Errorf("request with culture %s was sent with %s", culture, resp.Cursor)
+			}
+		}(cultures[i%len(cultures)])
+	}
+	// changing the default while requests are in flight is allowed
+	c.SetLanguageCode("en-GB")
+	wg.Wait()
+
+	if c.cultureCode != "en-US" {
+		t.Errorf("client culture code changed to %s", c.cultureCode)
+	}
+}
+
+func TestPortfolioWrites(t *testing.T) {
+	c := NewClient(nil, "access", "client")
+	c.BaseURL, _ = url.Parse("https://api.mews.test/api/connector/v1/")
+	c.Portfolio = true
+
+	addURL, _ := c.GetApiURL("customers/add")
+	_, err := c.NewRequestWithContext(context.Background(), addURL, &BaseRequest{})
+	if !errors.Is(err, ErrNoEnterprise) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	_, err = c.NewRequestWithContext(context.Background(), addURL, &BaseRequest{}, WithEnterpriseIDs("e1", "e2"))
+	if !errors.Is(err, ErrMultipleEnterprises) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	_, err = c.NewRequestWithContext(context.Background(), addURL, &BaseRequest{}, WithEnterpriseID("e1"))
+	if err != nil {
+		t.Error(err)
+	}
+
+	// reads without a scope return the data of all enterprises
+	getURL, _ := c.GetApiURL("customers/getAll")
+	req, err := c.NewRequestWithContext(context.Background(), getURL, &BaseRequest{}, WithEnterpriseID("e1"), WithEnterpriseIDs("e2", "e1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(req.Body)
+	if !strings.Contains(string(b), `"EnterpriseIds":["e1","e2"]`) {
+		t.Errorf("body %s isn't scoped to e1 and e2", b)
+	}
+}
+
+func TestPartitionByEnterprise(t *testing.T) {
+	type item struct {
+		ID           string
+		EnterpriseID string
+	}
+	items := []*item{{"1", "e1"}, {"2", "e2"}, {"3", "e1"}, nil}
+
+	partitions := PartitionByEnterprise(items)
+	if len(partitions["e1"]) != 2 || len(partitions["e2"]) != 1 || len(partitions[""]) != 1 {
+		t.Errorf("unexpected partitions %v", partitions)
+	}
+}
@@ -0,0 +1,172 @@
+package json
+
+import (
+	"context"
+	"iter"
+)
+
+const (
+	// DefaultPageSize is the page size used when neither the request nor the
+	// options specify a Limitation count. It is the maximum the API accepts.
+	DefaultPageSize = 1000
+)
+
+// PagedRequest is implemented by requests that support cursor based paging
+// through a Limitation.
+type PagedRequest interface {
+	GetContext() context.Context
+	GetLimitation() *Limitation
+}
+
+// PagedResponse is implemented by responses that return a Cursor pointing to
+// the next page.
+type PagedResponse interface {
+	GetCursor() string
+}
+
+type PageOptions struct {
+	// Number of items requested per page
+	PageSize int
+	// Stop after this many items have been yielded, 0 means no limit
+	MaxItems int
+	// Stop after this many pages have been fetched, 0 means no limit
+	MaxPages int
+}
+
+type PageOption func(*PageOptions)
+
+func WithPageSize(size int) PageOption {
+	return func(o *PageOptions) {
+		o.PageSize = size
+	}
+}
+
+func WithMaxItems(max int) PageOption {
+	return func(o *PageOptions) {
+		o.MaxItems = max
+	}
+}
+
+func WithMaxPages(max int) PageOption {
+	return func(o *PageOptions) {
+		o.MaxPages = max
+	}
+}
+
+func newPageOptions(req PagedRequest, opts []PageOption) PageOptions {
+	o := PageOptions{PageSize: req.GetLimitation().Count}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.PageSize <= 0 {
+		o.PageSize = DefaultPageSize
+	}
+	return o
+}
+
+// Pages returns an iterator over every page of a cursor paged endpoint. The
+// cursor of each response is copied into the Limitation of req before the next
+// page is requested. The Limitation is restored when the iteration ends, so
+// every iteration starts at the first page.
+func Pages[Req PagedRequest, Resp PagedResponse](req Req, fetch func(Req) (Resp, error), opts ...PageOption) iter.Seq2[Resp, error] {
+	o := newPageOptions(req, opts)
+	return func(yield func(Resp, error) bool) {
+		paginate(req, fetch, o, nil, yield)
+	}
+}
+
+// Items returns an iterator over the items of every page of a cursor paged
+// endpoint. items selects the collection to iterate from each response.
+func Items[Req PagedRequest, Resp PagedResponse, T any](req Req, fetch func(Req) (Resp, error), items func(Resp) []T, opts ...PageOption) iter.Seq2[T, error] {
+	o := newPageOptions(req, opts)
+	return func(yield func(T, error) bool) {
+		n := 0
+		paginate(req, fetch, o, func(resp Resp) int { return len(items(resp)) }, func(resp Resp, err error) bool {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return false
+			}
+
+			for _, item := range items(resp) {
+				if o.MaxItems > 0 && n >= o.MaxItems {
+					return false
+				}
+				if !yield(item, nil) {
+					return false
+				}
+				n++
+			}
+			return true
+		})
+	}
+}
+
+// CollectAll drains seq into a slice. When an error occurs the items collected
+// so far are returned together with the error.
+func CollectAll[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	all := []T{}
+	for item, err := range seq {
+		if err != nil {
+			return all, err
+		}
+		all = append(all, item)
+	}
+	return all, nil
+}
+
+func paginate[Req PagedRequest, Resp PagedResponse](req Req, fetch func(Req) (Resp, error), o PageOptions, count func(Resp) int, yield func(Resp, error) bool) {
+	limitation := req.GetLimitation()
+	start := *limitation
+	defer func() {
+		*limitation = start
+	}()
+
+	// page on a copy that is written to req before every fetch
+	page := start
+	fetched := 0
+	for i := 0; o.MaxPages <= 0 || i < o.MaxPages; i++ {
+		if err := req.GetContext().Err(); err != nil {
+			var zero Resp
+			yield(zero, err)
+			return
+		}
+
+		size := o.PageSize
+		if count != nil && o.MaxItems > 0 {
+			size = min(size, o.MaxItems-fetched)
+		}
+
+		page.Count = size
+		cursor := page.Cursor
+		*limitation = page
+
+		resp, err := fetch(req)
+		if err != nil {
+			yield(resp, err)
+			return
+		}
+
+		if !yield(resp, nil) {
+			return
+		}
+
+		next := resp.GetCursor()
+		if next == "" || next == cursor {
+			return
+		}
+		page.Cursor = next
+
+		if count != nil {
+			n := count(resp)
+			fetched += n
+			// a short page is the last page
+			if n < size {
+				return
+			}
+			if o.MaxItems > 0 && fetched >= o.MaxItems {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package json
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type testPagedRequest struct {
+	BaseRequest
+	Limitation Limitation
+}
+
+func (r *testPagedRequest) GetLimitation() *Limitation {
+	return &r.Limitation
+}
+
+type testPagedResponse struct {
+	Items  []int
+	Cursor string
+}
+
+func (r *testPagedResponse) GetCursor() string {
+	return r.Cursor
+}
+
+// testFetcher serves the integers 0..total-1 in pages, using the index of the
+// next item as cursor.
+func testFetcher(total int, calls *[]Limitation) func(*testPagedRequest) (*testPagedResponse, error) {
+	return func(req *testPagedRequest) (*testPagedResponse, error) {
+		*calls = append(*calls, req.Limitation)
+
+		start := 0
+		if req.Limitation.Cursor != "" {
+			start, _ = strconv.Atoi(req.Limitation.Cursor)
+		}
+		end := min(start+req.Limitation.Count, total)
+
+		resp := &testPagedResponse{}
+		for i := start; i < end; i++ {
+			resp.Items = append(resp.Items, i)
+		}
+		resp.Cursor = strconv.Itoa(end)
+		return resp, nil
+	}
+}
+
+func testItems(r *testPagedResponse) []int {
+	return r.Items
+}
+
+func TestItemsFollowsCursor(t *testing.T) {
+	calls := []Limitation{}
+	req := &testPagedRequest{}
+	items, err := CollectAll(Items(req, testFetcher(25, &calls), testItems, WithPageSize(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 25 {
+		t.Errorf("len(items) = %d, expected 25", len(items))
+	}
+	for i, item := range items {
+		if item != i {
+			t.Fatalf("items[%d] = %d, expected %d", i, item, i)
+		}
+	}
+
+	// the third page is short, so no fourth request should be made
+	if len(calls) != 3 {
+		t.Errorf("calls = %d, expected 3", len(calls))
+	}
+	if calls[1].Cursor != "10" || calls[2].Cursor != "20" {
+		t.Errorf("cursors = %q, %q, expected \"10\", \"20\"", calls[1].Cursor, calls[2].Cursor)
+	}
+}
+
+func TestItemsRestartsAtFirstPage(t *testing.T) {
+	calls := []Limitation{}
+	req := &testPagedRequest{Limitation: Limitation{Count: 10}}
+	seq := Items(req, testFetcher(25, &calls), testItems)
+
+	for i := 0; i < 2; i++ {
+		items, err := CollectAll(seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(items) != 25 || items[0] != 0 {
+			t.Errorf("iteration %d: items = %v, expected 0..24", i, items)
+		}
+		if req.Limitation != (Limitation{Count: 10}) {
+			t.Errorf("iteration %d: Limitation = %+v, expected it to be restored", i, req.Limitation)
+		}
+	}
+	if len(calls) != 6 {
+		t.Errorf("calls = %d, expected 6", len(calls))
+	}
+}
+
+func TestItemsMaxItems(t *testing.T) {
+	calls := []Limitation{}
+	req := &testPagedRequest{}
+	items, err := CollectAll(Items(req, testFetcher(100, &calls), testItems, WithPageSize(10), WithMaxItems(15)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 15 {
+		t.Errorf("len(items) = %d, expected 15", len(items))
+	}
+	if len(calls) != 2 || calls[1].Count != 5 {
+		t.Errorf("calls = %v, expected the second page to request 5 items", calls)
+	}
+}
+
+func TestItemsDefaultPageSize(t *testing.T) {
+	calls := []Limitation{}
+	req := &testPagedRequest{}
+	_, err := CollectAll(Items(req, testFetcher(5, &calls), testItems))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls[0].Count != DefaultPageSize {
+		t.Errorf("Count = %d, expected %d", calls[0].Count, DefaultPageSize)
+	}
+}
+
+func TestPagesStopsOnError(t *testing.T) {
+	errFetch := errors.New("fetch failed")
+	n := 0
+	fetch := func(req *testPagedRequest) (*testPagedResponse, error) {
+		n++
+		if n == 2 {
+			return nil, errFetch
+		}
+		return &testPagedResponse{Items: []int{1}, Cursor: strconv.Itoa(n)}, nil
+	}
+
+	pages := 0
+	var err error
+	for _, err = range Pages(&testPagedRequest{}, fetch) {
+		if err != nil {
+			break
+		}
+		pages++
+	}
+
+	if !errors.Is(err, errFetch) {
+		t.Errorf("err = %v, expected %v", err, errFetch)
+	}
+	if pages != 1 {
+		t.Errorf("pages = %d, expected 1", pages)
+	}
+}
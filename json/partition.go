@@ -0,0 +1,40 @@
+package json
+
+import (
+	"reflect"
+	"strings"
+)
+
+// PartitionByEnterprise groups the results of a call made with a portfolio
+// access token by enterprise, read from the EnterpriseID field of the items.
+// Items without the field, or with an empty one, are grouped under "".
+func PartitionByEnterprise[T any](items []T) map[string][]T {
+	partitions := map[string][]T{}
+	for _, item := range items {
+		id := enterpriseIDOf(reflect.ValueOf(item))
+		partitions[id] = append(partitions[id], item)
+	}
+	return partitions
+}
+
+// enterpriseIDOf returns the value of the EnterpriseID field of a struct or a
+// pointer to one.
+func enterpriseIDOf(v reflect.Value) string {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+
+	f := v.FieldByNameFunc(func(name string) bool {
+		return strings.EqualFold(name, "EnterpriseID")
+	})
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
@@ -41,6 +41,10 @@ func (req *BaseRequest) SetCultureCode(code string) {
 	req.CultureCode = code
 }
 
+func (req *BaseRequest) SetClient(name string) {
+	req.Client = name
+}
+
 type Limitation struct {
 	Cursor string `json:"Cursor,omitempty"`
 	Count  int    `json:"Count,omitempty"`
@@ -3,63 +3,114 @@ package json
 import (
 	gojson "encoding/json"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
+	"strings"
 
 	httperr "github.com/omniboost/go-httperr"
 )
 
 // CheckResponse checks the API response for errors, and returns them if present. A response is considered an
 // error if it has a status code outside the 200 range. API error responses are expected to have either no response
-// body, or a XML response body that maps to ErrorResponse. Any other response body will be silently ignored.
+// body, or a JSON response body that maps to ErrorResponse. Any other response body will be silently ignored.
+//
+// The returned error is a *httperr.Error wrapping one of the typed errors in errors.go, so both errors.As and
+// errors.Is can be used to inspect it.
 func CheckResponse(r *http.Response) error {
 	if c := r.StatusCode; c >= 200 && c <= 299 {
 		return nil
 	}
 
 	// we have an error
-	errorResponse := &ErrorResponse{Response: r}
-
-	// if we have no body, return the error response now
-	if r.Body != nil {
-		errorResponse.Message = r.Status
-		// wrap error in http error so we can handle it properly
-		return &httperr.Error{StatusCode: r.StatusCode, Err: errorResponse}
+	errorResponse := &ErrorResponse{
+		Response:  r,
+		RequestID: r.Header.Get("Request-Id"),
 	}
-
-	data, err := ioutil.ReadAll(r.Body)
-	if err != nil {
-		return errorResponse
+	if r.Request != nil && r.Request.URL != nil {
+		errorResponse.Path = r.Request.URL.Path
 	}
 
-	if len(data) == 0 {
-		return errorResponse
+	if r.Body != nil {
+		data, err := io.ReadAll(r.Body)
+		if err == nil && len(data) > 0 {
+			// failed to unmarshal: the message is set to the status below
+			_ = gojson.Unmarshal(data, errorResponse)
+		}
 	}
 
-	err = gojson.Unmarshal(data, errorResponse)
-	if err == nil {
-		return errorResponse
+	if errorResponse.Message == "" {
+		errorResponse.Message = r.Status
 	}
 
-	// failed to unmarshal, set message to status
-	errorResponse.Message = r.Status
 	// wrap error in http error so we can handle it properly
-	return &httperr.Error{StatusCode: r.StatusCode, Err: errorResponse}
-
+	return &httperr.Error{StatusCode: r.StatusCode, Err: newAPIError(errorResponse)}
 }
 
 type ErrorResponse struct {
 	// HTTP response that caused this error
 	Response *http.Response
 
+	// Path of the API endpoint that was requested
+	Path string `json:"-"`
+
 	// Fault code
 	Details string `json:"Details"`
 
 	// Fault message
 	Message string `json:"Message"`
+
+	// Identifier of the request, taken from the Request-Id header or the body
+	RequestID string `json:"RequestId"`
+}
+
+func (r *ErrorResponse) UnmarshalJSON(data []byte) error {
+	type alias struct {
+		Details   gojson.RawMessage `json:"Details"`
+		Message   string            `json:"Message"`
+		RequestID string            `json:"RequestId"`
+	}
+
+	a := alias{}
+	err := gojson.Unmarshal(data, &a)
+	if err != nil {
+		return err
+	}
+
+	r.Message = a.Message
+	if a.RequestID != "" && r.RequestID == "" {
+		r.RequestID = a.RequestID
+	}
+
+	// Details is a string most of the time, but can be an object
+	if len(a.Details) > 0 && string(a.Details) != "null" {
+		var details string
+		if err := gojson.Unmarshal(a.Details, &details); err == nil {
+			r.Details = details
+		} else {
+			r.Details = string(a.Details)
+		}
+	}
+	return nil
+}
+
+func (r *ErrorResponse) StatusCode() int {
+	if r.Response == nil {
+		return 0
+	}
+	return r.Response.StatusCode
 }
 
 func (r *ErrorResponse) Error() string {
-	return fmt.Sprintf("%v %v: %d (%v %v)",
-		r.Response.Request.Method, r.Response.Request.URL, r.Response.StatusCode, r.Details, r.Message)
+	method := ""
+	url := r.Path
+	if r.Response != nil && r.Response.Request != nil {
+		method = r.Response.Request.Method
+		url = r.Response.Request.URL.String()
+	}
+
+	msg := strings.TrimSpace(fmt.Sprintf("%v %v", r.Details, r.Message))
+	if r.RequestID != "" {
+		return fmt.Sprintf("%v %v: %d (%v) [request id: %v]", method, url, r.StatusCode(), msg, r.RequestID)
+	}
+	return fmt.Sprintf("%v %v: %d (%v)", method, url, r.StatusCode(), msg)
 }
@@ -0,0 +1,99 @@
+package json
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	httperr "github.com/omniboost/go-httperr"
+)
+
+func testErrorResponse(statusCode int, body string, header http.Header) *http.Response {
+	u, _ := url.Parse("https://api.mews.com/api/connector/v1/customers/getAll")
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:     http.StatusText(statusCode),
+		StatusCode: statusCode,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    &http.Request{Method: http.MethodPost, URL: u},
+	}
+}
+
+func TestCheckResponseDecodesBody(t *testing.T) {
+	header := http.Header{}
+	header.Set("Request-Id", "abc-123")
+	resp := testErrorResponse(http.StatusBadRequest, `{"Message":"Invalid Limitation.","Details":null}`, header)
+
+	err := CheckResponse(resp)
+
+	validationErr := &ValidationError{}
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("err = %T, expected *ValidationError", err)
+	}
+	if validationErr.Message != "Invalid Limitation." {
+		t.Errorf("Message = %q, expected %q", validationErr.Message, "Invalid Limitation.")
+	}
+	if validationErr.RequestID != "abc-123" {
+		t.Errorf("RequestID = %q, expected %q", validationErr.RequestID, "abc-123")
+	}
+	if validationErr.Path != "/api/connector/v1/customers/getAll" {
+		t.Errorf("Path = %q", validationErr.Path)
+	}
+	if !errors.Is(err, ErrValidation) {
+		t.Error("errors.Is(err, ErrValidation) = false")
+	}
+
+	httpErr := &httperr.Error{}
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("err doesn't wrap a httperr.Error with status code 400")
+	}
+}
+
+func TestCheckResponseTypes(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		target     error
+	}{
+		{http.StatusUnauthorized, ErrInvalidToken},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusRequestTimeout, ErrThrottled},
+		{http.StatusTooManyRequests, ErrThrottled},
+		{http.StatusInternalServerError, ErrServer},
+		{http.StatusServiceUnavailable, ErrServer},
+	}
+
+	for _, tt := range tests {
+		err := CheckResponse(testErrorResponse(tt.statusCode, "", nil))
+		if !errors.Is(err, tt.target) {
+			t.Errorf("%d: errors.Is(%v, %v) = false", tt.statusCode, err, tt.target)
+		}
+
+		errorResponse := &ErrorResponse{}
+		if !errors.As(err, &errorResponse) {
+			t.Errorf("%d: err doesn't wrap an *ErrorResponse", tt.statusCode)
+		}
+		if errorResponse.Message != http.StatusText(tt.statusCode) {
+			t.Errorf("%d: Message = %q, expected the status", tt.statusCode, errorResponse.Message)
+		}
+	}
+}
+
+func TestCheckResponseRetryAfter(t *testing.T) {
+	header := http.Header{}
+	header.Set("Retry-After", "30")
+	err := CheckResponse(testErrorResponse(http.StatusTooManyRequests, "", header))
+
+	throttledErr := &ThrottledError{}
+	if !errors.As(err, &throttledErr) {
+		t.Fatalf("err = %T, expected *ThrottledError", err)
+	}
+	if throttledErr.RetryAfter.Seconds() != 30 {
+		t.Errorf("RetryAfter = %v, expected 30s", throttledErr.RetryAfter)
+	}
+}
@@ -0,0 +1,216 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	ctxRetryNonIdempotent = ContextKey("retry-non-idempotent")
+
+	idempotentMu        sync.RWMutex
+	idempotentEndpoints = map[string]bool{}
+)
+
+// RetryPolicy decides whether a failed attempt is sent again.
+type RetryPolicy interface {
+	// Retry returns whether the request should be sent again and how long to
+	// wait before doing so.
+	Retry(attempt Attempt) (time.Duration, bool)
+}
+
+// Attempt describes a failed attempt of a request.
+type Attempt struct {
+	// Endpoint relative to the BaseURL, e.g. "customers/getAll"
+	Endpoint string
+	// Number of the attempt that failed, starting at 1
+	Number int
+	// Whether the endpoint can safely be sent more than once, or the caller
+	// opted in to retrying it anyway
+	Idempotent bool
+	// Response of the attempt, nil when the request failed in transport
+	Response *http.Response
+	Err      error
+	// Wait requested by the API through the Retry-After header
+	RetryAfter time.Duration
+}
+
+// BackoffRetryPolicy retries retryable failures with an exponential backoff
+// and jitter. Requests to non-idempotent endpoints are never retried unless
+// RetryNonIdempotent is set or the request context was created with
+// WithRetryNonIdempotent.
+type BackoffRetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// Fraction of the delay that is randomly added or subtracted
+	Jitter float64
+	// Retry requests that timed out
+	RetryTimeouts bool
+	// Also retry endpoints that aren't idempotent, e.g. payments/addExternal
+	RetryNonIdempotent bool
+}
+
+func DefaultRetryPolicy() *BackoffRetryPolicy {
+	return &BackoffRetryPolicy{
+		MaxRetries:    3,
+		BaseDelay:     500 * time.Millisecond,
+		MaxDelay:      30 * time.Second,
+		Jitter:        0.2,
+		RetryTimeouts: true,
+	}
+}
+
+func (p *BackoffRetryPolicy) Retry(attempt Attempt) (time.Duration, bool) {
+	if attempt.Number > p.MaxRetries {
+		return 0, false
+	}
+
+	if !attempt.Idempotent && !p.RetryNonIdempotent {
+		return 0, false
+	}
+
+	if !IsRetryable(attempt.Response, attempt.Err) {
+		return 0, false
+	}
+
+	if !p.RetryTimeouts && isTimeout(attempt.Err) {
+		return 0, false
+	}
+
+	return max(p.Backoff(attempt.Number), attempt.RetryAfter), true
+}
+
+// Backoff returns the delay after attempt n: BaseDelay doubled for every
+// attempt, capped at MaxDelay, with jitter applied.
+func (p *BackoffRetryPolicy) Backoff(n int) time.Duration {
+	delay := p.BaseDelay
+	for i := 1; i < n && (p.MaxDelay <= 0 || delay < p.MaxDelay); i++ {
+		delay *= 2
+	}
+	if p.MaxDelay > 0 && delay > p.MaxDelay {
+		delay = p.MaxDelay
+	}
+
+	if p.Jitter > 0 {
+		delta := (rand.Float64()*2 - 1) * p.Jitter * float64(delay)
+		delay += time.Duration(delta)
+	}
+	return max(delay, 0)
+}
+
+// IsRetryable reports whether a failure is transient: timeouts, connection
+// resets, throttling (408/429) and server errors (5xx).
+func IsRetryable(resp *http.Response, err error) bool {
+	if errors.Is(err, ErrThrottled) || errors.Is(err, ErrServer) {
+		return true
+	}
+
+	if resp != nil {
+		switch code := resp.StatusCode; {
+		case code == http.StatusRequestTimeout, code == http.StatusTooManyRequests:
+			return true
+		case code >= 500 && code <= 599:
+			return true
+		}
+	}
+
+	if err == nil {
+		return false
+	}
+
+	if isTimeout(err) {
+		return true
+	}
+
+	return errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF)
+}
+
+func isTimeout(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsIdempotent reports whether a request to endpoint can safely be sent more
+// than once. Endpoints registered with SetIdempotent use the registered value,
+// otherwise only read operations (get, getAll, getPdf, ...) are idempotent.
+func IsIdempotent(endpoint string) bool {
+	idempotentMu.RLock()
+	idempotent, ok := idempotentEndpoints[endpoint]
+	idempotentMu.RUnlock()
+	if ok {
+		return idempotent
+	}
+
+	parts := strings.Split(strings.Trim(endpoint, "/"), "/")
+	if len(parts) < 2 {
+		return false
+	}
+	return strings.HasPrefix(parts[1], "get")
+}
+
+// SetIdempotent marks endpoint as safe (true) or unsafe (false) to retry. In
+// portfolio mode idempotent endpoints are scoped like reads, the others like
+// writes.
+func SetIdempotent(endpoint string, idempotent bool) {
+	idempotentMu.Lock()
+	defer idempotentMu.Unlock()
+	idempotentEndpoints[endpoint] = idempotent
+}
+
+// WithRetryNonIdempotent returns a context that allows the retry policy to
+// retry a request to a non-idempotent endpoint. Use it with
+// BaseRequest.SetContext, only for requests that can't create duplicates.
+func WithRetryNonIdempotent(ctx context.Context) context.Context {
+	return context.WithValue(ctx, ctxRetryNonIdempotent, true)
+}
+
+func retryNonIdempotent(ctx context.Context) bool {
+	allow, _ := ctx.Value(ctxRetryNonIdempotent).(bool)
+	return allow
+}
+
+func retryAfterFromError(err error) time.Duration {
+	var throttledErr *ThrottledError
+	if errors.As(err, &throttledErr) {
+		return throttledErr.RetryAfter
+	}
+	return 0
+}
+
+// sleep waits for d or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
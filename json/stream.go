@@ -0,0 +1,184 @@
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// StreamDecoder is implemented by responses that decode the body themselves.
+// Client.Do passes them the decoder of the body instead of decoding it, and a
+// DecodeFunc to decode the parts of the body they read.
+type StreamDecoder interface {
+	DecodeStream(dec *json.Decoder, decode DecodeFunc) error
+}
+
+// DecodeFunc decodes data, the JSON value at path of a response, into v with
+// the DisallowUnknownFields and ReportUnknownFields settings of the client.
+type DecodeFunc func(path string, data []byte, v interface{}) error
+
+// Stream is a response that passes the elements of one array, e.g. the
+// OrderItems of an orderitems.AllResponse, to a function as they are decoded.
+// Only one element is kept in memory at a time. The other fields, such as the
+// Cursor and sibling collections, are decoded into Response once the body has
+// been read.
+//
+// When an attempt fails halfway and is retried, the elements that were already
+// passed are skipped, so every element is passed once.
+type Stream[T any] struct {
+	// JSON name of the array, matched without regard to case like
+	// encoding/json does
+	Field    string
+	Response interface{}
+	Item     func(item T) error
+
+	// number of elements passed to Item by earlier attempts
+	passed int
+}
+
+// NewStream returns a stream that passes the elements of the array field to
+// item and decodes the rest of the body into response.
+func NewStream[T any](field string, response interface{}, item func(item T) error) *Stream[T] {
+	return &Stream[T]{Field: field, Response: response, Item: item}
+}
+
+func (s *Stream[T]) DecodeStream(dec *json.Decoder, decode DecodeFunc) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return err
+	}
+
+	// the other fields are collected and decoded into Response at the end
+	rest := &bytes.Buffer{}
+	rest.WriteByte('{')
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		if strings.EqualFold(key, s.Field) {
+			if err := s.decodeArray(dec, decode, key+"[]"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw := json.RawMessage{}
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if rest.Len() > 1 {
+			rest.WriteByte(',')
+		}
+		k, _ := json.Marshal(key)
+		rest.Write(k)
+		rest.WriteByte(':')
+		rest.Write(raw)
+	}
+
+	if err := expectDelim(dec, '}'); err != nil {
+		return err
+	}
+	rest.WriteByte('}')
+
+	if s.Response == nil {
+		return nil
+	}
+	return decode("", rest.Bytes(), s.Response)
+}
+
+func (s *Stream[T]) decodeArray(dec *json.Decoder, decode DecodeFunc, path string) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok == nil {
+		// null
+		return nil
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("%s: expected an array, got %v", s.Field, tok)
+	}
+
+	for i := 0; dec.More(); i++ {
+		raw := json.RawMessage{}
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		if i < s.passed {
+			continue
+		}
+
+		var item T
+		if err := decode(path, raw, &item); err != nil {
+			return err
+		}
+		if err := s.Item(item); err != nil {
+			return err
+		}
+		s.passed++
+	}
+
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("expected %v, got %v", delim, tok)
+	}
+	return nil
+}
+
+// errStopStream stops a stream when the consumer of an iterator stops early.
+var errStopStream = errors.New("stream stopped")
+
+// StreamItems returns an iterator over the elements of every page of a cursor
+// paged endpoint, decoded one at a time. fetch requests a page and passes its
+// elements to item, e.g. orderitems.Service.AllStream. Like Items, the cursor
+// of each response is copied back into the Limitation of req.
+func StreamItems[Req PagedRequest, Resp PagedResponse, T any](req Req, fetch func(req Req, item func(T) error) (Resp, error), opts ...PageOption) iter.Seq2[T, error] {
+	o := newPageOptions(req, opts)
+	return func(yield func(T, error) bool) {
+		n, stopped := 0, false
+		// counts the elements of the page, for paginate to detect the last one
+		page := 0
+		fetchPage := func(req Req) (Resp, error) {
+			page = 0
+			resp, err := fetch(req, func(item T) error {
+				page++
+				if o.MaxItems > 0 && n >= o.MaxItems {
+					stopped = true
+					return errStopStream
+				}
+				if !yield(item, nil) {
+					stopped = true
+					return errStopStream
+				}
+				n++
+				return nil
+			})
+			if errors.Is(err, errStopStream) {
+				err = nil
+			}
+			return resp, err
+		}
+
+		paginate(req, fetchPage, o, func(Resp) int { return page }, func(resp Resp, err error) bool {
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return false
+			}
+			return !stopped
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package json
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func (r *testChunkResponse) GetCursor() string {
+	return r.Cursor
+}
+
+func TestStreamDecode(t *testing.T) {
+	body := `{"Cursor":"c","items":[{"ID":"1"},{"ID":"2"}],"Customers":[{"ID":"customer"}]}`
+
+	items := []string{}
+	resp := &testChunkResponse{}
+	stream := NewStream("Items", resp, func(item testChunkItem) error {
+		items = append(items, item.ID)
+		return nil
+	})
+	decode := func(path string, data []byte, v interface{}) error {
+		return json.Unmarshal(data, v)
+	}
+	if err := stream.DecodeStream(json.NewDecoder(strings.NewReader(body)), decode); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Join(items, ",") != "1,2" {
+		t.Errorf("items = %v, expected 1,2", items)
+	}
+	if len(resp.Items) != 0 {
+		t.Errorf("len(Items) = %d, expected the items not to be kept", len(resp.Items))
+	}
+	if resp.Cursor != "c" || len(resp.Customers) != 1 {
+		t.Errorf("resp = %+v, expected the cursor and customers", resp)
+	}
+}
+
+// newStreamClient returns a client for a server that pages through total
+// items, using the index of the next item as cursor. The first response is cut
+// off after one item when truncate is set.
+func newStreamClient(t *testing.T, total int, truncate bool) (*Client, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	c := newServerClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		req := testPagedRequest{}
+		json.NewDecoder(r.Body).Decode(&req)
+
+		start, _ := strconv.Atoi(req.Limitation.Cursor)
+		end := min(start+req.Limitation.Count, total)
+		items := []string{}
+		for i := start; i < end; i++ {
+			items = append(items, fmt.Sprintf(`{"ID":"%d"}`, i))
+		}
+		body := fmt.Sprintf(`{"Items":[%s],"Cursor":"%d"}`, strings.Join(items, ","), end)
+
+		if truncate && n == 1 {
+			w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+			w.Write([]byte(body[:strings.Index(body, "},")+2]))
+			return
+		}
+		w.Write([]byte(body))
+	})
+	c.RetryPolicy = &BackoffRetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond}
+	return c, calls
+}
+
+func testStreamFetch(c *Client) func(*testPagedRequest, func(testChunkItem) error) (*testChunkResponse, error) {
+	return func(req *testPagedRequest, item func(testChunkItem) error) (*testChunkResponse, error) {
+		apiURL, err := c.GetApiURL("orderItems/getAll")
+		if err != nil {
+			return nil, err
+		}
+		httpReq, err := c.NewRequest(apiURL, req)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &testChunkResponse{}
+		_, err = c.Do(httpReq, NewStream("Items", resp, item))
+		return resp, err
+	}
+}
+
+func TestStreamItems(t *testing.T) {
+	c, calls := newStreamClient(t, 5, false)
+
+	ids := []string{}
+	for item, err := range StreamItems(&testPagedRequest{}, testStreamFetch(c), WithPageSize(2)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	if strings.Join(ids, ",") != "0,1,2,3,4" {
+		t.Errorf("items = %v, expected 0-4", ids)
+	}
+	// the third page is short, so no fourth request should be made
+	if n := calls.Load(); n != 3 {
+		t.Errorf("%d requests, expected 3", n)
+	}
+}
+
+func TestStreamItemsMaxItems(t *testing.T) {
+	c, calls := newStreamClient(t, 10, false)
+
+	items, err := CollectAll(StreamItems(&testPagedRequest{}, testStreamFetch(c), WithPageSize(2), WithMaxItems(3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 3 {
+		t.Errorf("len(items) = %d, expected 3", len(items))
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("%d requests, expected 2", n)
+	}
+}
+
+func TestStreamRetrySkipsPassedItems(t *testing.T) {
+	c, calls := newStreamClient(t, 3, true)
+
+	ids := []string{}
+	for item, err := range StreamItems(&testPagedRequest{}, testStreamFetch(c), WithPageSize(5)) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, item.ID)
+	}
+
+	if strings.Join(ids, ",") != "0,1,2" {
+		t.Errorf("items = %v, expected every item once", ids)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Errorf("%d requests, expected a retry of the cut off response", n)
+	}
+}
@@ -0,0 +1,208 @@
+package json
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrUnknownEnterprise = errors.New("No tokens for enterprise")
+)
+
+// Tokens are the credentials of a request.
+type Tokens struct {
+	AccessToken string `json:"AccessToken" yaml:"AccessToken"`
+	ClientToken string `json:"ClientToken" yaml:"ClientToken"`
+}
+
+// check returns ErrNoAccessToken or ErrNoClientToken when a token is missing.
+func (t Tokens) check() error {
+	if t.AccessToken == "" {
+		return ErrNoAccessToken
+	}
+
+	if t.ClientToken == "" {
+		return ErrNoClientToken
+	}
+
+	return nil
+}
+
+// TokenProvider resolves the tokens of every request and websocket
+// connection, so tokens can differ per enterprise and be rotated without
+// creating a new client. ctx carries the RequestOptions of the call, e.g. its
+// EnterpriseID.
+type TokenProvider interface {
+	Tokens(ctx context.Context) (Tokens, error)
+}
+
+// TokenError is returned when a TokenProvider can't resolve the tokens of a
+// call.
+type TokenError struct {
+	// Provider that failed, e.g. "env"
+	Provider     string
+	EnterpriseID string
+	Err          error
+}
+
+func (e *TokenError) Error() string {
+	if e.EnterpriseID != "" {
+		return fmt.Sprintf("%s tokens of enterprise %s: %s", e.Provider, e.EnterpriseID, e.Err)
+	}
+	return fmt.Sprintf("%s tokens: %s", e.Provider, e.Err)
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+// ResolveTokens resolves tokens with provider. Failures are returned as a
+// *TokenError.
+func ResolveTokens(ctx context.Context, provider TokenProvider) (Tokens, error) {
+	tokens, err := provider.Tokens(ctx)
+	var terr *TokenError
+	if err != nil && !errors.As(err, &terr) {
+		err = &TokenError{Provider: fmt.Sprintf("%T", provider), EnterpriseID: OptionsFromContext(ctx).EnterpriseID, Err: err}
+	}
+	return tokens, err
+}
+
+// StaticTokens always provides the same tokens.
+type StaticTokens Tokens
+
+func (t StaticTokens) Tokens(ctx context.Context) (Tokens, error) {
+	tokens := Tokens(t)
+	if err := tokens.check(); err != nil {
+		return Tokens{}, &TokenError{Provider: "static", Err: err}
+	}
+	return tokens, nil
+}
+
+// EnvTokens reads the tokens from environment variables on every call. Empty
+// names default to MEWS_ACCESS_TOKEN and MEWS_CLIENT_TOKEN.
+type EnvTokens struct {
+	AccessTokenVar string
+	ClientTokenVar string
+}
+
+func (t EnvTokens) Tokens(ctx context.Context) (Tokens, error) {
+	accessTokenVar := firstNonEmpty(t.AccessTokenVar, "MEWS_ACCESS_TOKEN")
+	clientTokenVar := firstNonEmpty(t.ClientTokenVar, "MEWS_CLIENT_TOKEN")
+
+	tokens := Tokens{
+		AccessToken: os.Getenv(accessTokenVar),
+		ClientToken: os.Getenv(clientTokenVar),
+	}
+	if err := tokens.check(); err != nil {
+		return Tokens{}, &TokenError{Provider: "env", Err: fmt.Errorf("%s, %s: %w", accessTokenVar, clientTokenVar, err)}
+	}
+	return tokens, nil
+}
+
+// Credentials is the content of a credentials file: the tokens of every
+// enterprise, with defaults for calls that don't name an enterprise. An
+// enterprise without a ClientToken uses the default one.
+//
+//	ClientToken: client-token
+//	Enterprises:
+//	  3fa85f64-5717-4562-b3fc-2c963f66afa6:
+//	    AccessToken: access-token
+type Credentials struct {
+	Tokens      `yaml:",inline"`
+	Enterprises map[string]Tokens `json:"Enterprises" yaml:"Enterprises"`
+}
+
+// tokens returns the tokens of an enterprise, or the defaults when enterpriseID
+// is empty.
+func (c Credentials) tokens(enterpriseID string) (Tokens, error) {
+	if enterpriseID == "" {
+		return c.Tokens, c.Tokens.check()
+	}
+
+	tokens, ok := c.Enterprises[enterpriseID]
+	if !ok {
+		return Tokens{}, ErrUnknownEnterprise
+	}
+	tokens.ClientToken = firstNonEmpty(tokens.ClientToken, c.ClientToken)
+	return tokens, tokens.check()
+}
+
+// FileTokens provides the tokens of the enterprise of a call from a JSON or
+// YAML credentials file, see Credentials. Files ending in .yaml or .yml are
+// read as YAML. The file is read again when it changes, so tokens are rotated
+// by replacing it.
+type FileTokens struct {
+	Path string
+
+	mu          sync.Mutex
+	modTime     time.Time
+	credentials Credentials
+}
+
+// NewFileTokens returns a provider for the credentials file at path, which is
+// read right away so a broken file fails early.
+func NewFileTokens(path string) (*FileTokens, error) {
+	t := &FileTokens{Path: path}
+	if _, err := t.load(); err != nil {
+		return nil, &TokenError{Provider: "file", Err: err}
+	}
+	return t, nil
+}
+
+func (t *FileTokens) Tokens(ctx context.Context) (Tokens, error) {
+	enterpriseID := OptionsFromContext(ctx).EnterpriseID
+
+	credentials, err := t.load()
+	if err != nil {
+		return Tokens{}, &TokenError{Provider: "file", EnterpriseID: enterpriseID, Err: err}
+	}
+
+	tokens, err := credentials.tokens(enterpriseID)
+	if err != nil {
+		return Tokens{}, &TokenError{Provider: "file", EnterpriseID: enterpriseID, Err: err}
+	}
+	return tokens, nil
+}
+
+// load returns the credentials in the file, reading it again when it changed.
+func (t *FileTokens) load() (Credentials, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	info, err := os.Stat(t.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+	if !t.modTime.IsZero() && info.ModTime().Equal(t.modTime) {
+		return t.credentials, nil
+	}
+
+	b, err := os.ReadFile(t.Path)
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	credentials := Credentials{}
+	switch strings.ToLower(filepath.Ext(t.Path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &credentials)
+	default:
+		err = json.Unmarshal(b, &credentials)
+	}
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%s: %w", t.Path, err)
+	}
+
+	t.modTime = info.ModTime()
+	t.credentials = credentials
+	return credentials, nil
+}
@@ -0,0 +1,164 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTokens(t *testing.T) {
+	files := map[string]string{
+		"credentials.json": `{
+			"ClientToken": "client",
+			"AccessToken": "default",
+			"Enterprises": {
+				"e1": {"AccessToken": "access-e1"},
+				"e2": {"AccessToken": "access-e2", "ClientToken": "client-e2"}
+			}
+		}`,
+		"credentials.yaml": `
+ClientToken: client
+AccessToken: default
+Enterprises:
+  e1:
+    AccessToken: access-e1
+  e2:
+    AccessToken: access-e2
+    ClientToken: client-e2
+`,
+	}
+
+	for name, content := range files {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		provider, err := NewFileTokens(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tests := []struct {
+			enterpriseID string
+			expected     Tokens
+		}{
+			{"", Tokens{AccessToken: "default", ClientToken: "client"}},
+			{"e1", Tokens{AccessToken: "access-e1", ClientToken: "client"}},
+			{"e2", Tokens{AccessToken: "access-e2", ClientToken: "client-e2"}},
+		}
+		for _, test := range tests {
+			ctx := WithOptions(context.Background(), WithEnterpriseID(test.enterpriseID))
+			tokens, err := provider.Tokens(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tokens != test.expected {
+				t.Errorf("%s: tokens of %q are %+v, expected %+v", name, test.enterpriseID, tokens, test.expected)
+			}
+		}
+
+		ctx := WithOptions(context.Background(), WithEnterpriseID("e3"))
+		_, err = provider.Tokens(ctx)
+		var terr *TokenError
+		if !errors.As(err, &terr) || terr.EnterpriseID != "e3" || !errors.Is(err, ErrUnknownEnterprise) {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+}
+
+func TestFileTokensRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	if err := os.WriteFile(path, []byte(`{"AccessToken": "old", "ClientToken": "client"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	provider, err := NewFileTokens(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"AccessToken": "new", "ClientToken": "client"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// make the change visible on file systems with a coarse modification time
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	tokens, err := provider.Tokens(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens.AccessToken != "new" {
+		t.Errorf("access token is %s, expected new", tokens.AccessToken)
+	}
+}
+
+func TestEnvTokens(t *testing.T) {
+	t.Setenv("TEST_ACCESS_TOKEN", "access")
+	t.Setenv("TEST_CLIENT_TOKEN", "")
+
+	provider := EnvTokens{AccessTokenVar: "TEST_ACCESS_TOKEN", ClientTokenVar: "TEST_CLIENT_TOKEN"}
+	_, err := provider.Tokens(context.Background())
+	if !errors.Is(err, ErrNoClientToken) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	t.Setenv("TEST_CLIENT_TOKEN", "client")
+	tokens, err := provider.Tokens(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens != (Tokens{AccessToken: "access", ClientToken: "client"}) {
+		t.Errorf("unexpected tokens %+v", tokens)
+	}
+}
+
+type enterpriseTokens map[string]Tokens
+
+func (p enterpriseTokens) Tokens(ctx context.Context) (Tokens, error) {
+	tokens, ok := p[OptionsFromContext(ctx).EnterpriseID]
+	if !ok {
+		return Tokens{}, ErrUnknownEnterprise
+	}
+	return tokens, nil
+}
+
+func TestNewRequestTokenProvider(t *testing.T) {
+	c := NewClient(nil, "", "")
+	c.BaseURL, _ = url.Parse("https://api.mews.test/api/connector/v1/")
+	c.TokenProvider = enterpriseTokens{"e1": {AccessToken: "access-e1", ClientToken: "client"}}
+	apiURL, _ := c.GetApiURL("customers/getAll")
+
+	req, err := c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{}, WithEnterpriseID("e1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := io.ReadAll(req.Body)
+	if !strings.Contains(string(b), `"AccessToken":"access-e1"`) {
+		t.Errorf("body %s doesn't contain the access token of e1", b)
+	}
+	if token := accessTokenFromContext(req.Context()); token != "access-e1" {
+		t.Errorf("access token in context is %s", token)
+	}
+
+	// errors of providers are typed
+	_, err = c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{}, WithEnterpriseID("e2"))
+	var terr *TokenError
+	if !errors.As(err, &terr) || terr.EnterpriseID != "e2" || !errors.Is(err, ErrUnknownEnterprise) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	c.TokenProvider = nil
+	_, err = c.NewRequestWithContext(context.Background(), apiURL, &BaseRequest{})
+	if !errors.As(err, &terr) || !errors.Is(err, ErrNoAccessToken) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
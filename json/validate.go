@@ -0,0 +1,131 @@
+package json
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// Documented limits of the Connector API
+	MaxLimitationCount = 1000
+	MaxIDs             = 1000
+	MaxIntervalMonths  = 3
+)
+
+// Validator is implemented by requests that can check the documented
+// constraints of their endpoint. The client validates requests before they are
+// sent.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is a constraint a field of a request violates.
+type FieldError struct {
+	// JSON name of the field, e.g. "UpdatedUtc"
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// InvalidRequestError is returned before a request is sent when it violates
+// the constraints of its endpoint. It matches ErrValidation, like the
+// ValidationError of a 400 response.
+type InvalidRequestError struct {
+	Fields []FieldError
+}
+
+func (e *InvalidRequestError) Error() string {
+	msgs := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		msgs[i] = f.Error()
+	}
+	return fmt.Sprintf("Invalid request: %s", strings.Join(msgs, "; "))
+}
+
+func (e *InvalidRequestError) Is(target error) bool { return target == ErrValidation }
+
+// Validation collects the field errors of a request:
+//
+//	v := &json.Validation{}
+//	v.MaxItems("CustomerIds", len(r.CustomerIDs), json.MaxIDs)
+//	return v.Err()
+type Validation struct {
+	fields []FieldError
+}
+
+func (v *Validation) Add(field string, format string, args ...interface{}) {
+	v.fields = append(v.fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Required adds an error for field when ok is false.
+func (v *Validation) Required(field string, ok bool) {
+	if !ok {
+		v.Add(field, "is required")
+	}
+}
+
+// AnyOf adds an error when none of the filters fields is set.
+func (v *Validation) AnyOf(set bool, fields ...string) {
+	if !set {
+		v.Add(strings.Join(fields, ", "), "one of the filters is required")
+	}
+}
+
+// MaxItems adds an error for a list field with more than max items.
+func (v *Validation) MaxItems(field string, n int, max int) {
+	if n > max {
+		v.Add(field, "has %d items, at most %d are allowed", n, max)
+	}
+}
+
+// Interval adds an error for an interval that ends before it starts or is
+// longer than months. An empty interval is valid.
+func (v *Validation) Interval(field string, start time.Time, end time.Time, months int) {
+	if start.IsZero() && end.IsZero() {
+		return
+	}
+	if start.IsZero() || end.IsZero() {
+		v.Add(field, "needs both a start and an end")
+		return
+	}
+	if end.Before(start) {
+		v.Add(field, "ends before it starts")
+		return
+	}
+	if months > 0 && end.After(start.AddDate(0, months, 0)) {
+		v.Add(field, "is longer than %d months", months)
+	}
+}
+
+// Err returns the collected errors as an *InvalidRequestError, or nil.
+func (v *Validation) Err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &InvalidRequestError{Fields: v.fields}
+}
+
+// Validate checks the Limitation of a paged request and runs the Validate
+// method of requests that implement Validator.
+func Validate(requestBody interface{}) error {
+	v := &Validation{}
+	if r, ok := requestBody.(PagedRequest); ok {
+		if count := r.GetLimitation().Count; count < 0 || count > MaxLimitationCount {
+			v.Add("Limitation.Count", "is %d, must be between 1 and %d", count, MaxLimitationCount)
+		}
+	}
+	if r, ok := requestBody.(Validator); ok {
+		if err := r.Validate(); err != nil {
+			rerr, ok := err.(*InvalidRequestError)
+			if !ok {
+				return err
+			}
+			v.fields = append(v.fields, rerr.Fields...)
+		}
+	}
+	return v.Err()
+}
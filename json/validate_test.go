@@ -0,0 +1,79 @@
+package json
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type validatedRequest struct {
+	BaseRequest
+	Limitation  Limitation
+	CustomerIDs []string
+	Start, End  time.Time
+}
+
+func (r *validatedRequest) GetLimitation() *Limitation {
+	return &r.Limitation
+}
+
+func (r *validatedRequest) Validate() error {
+	v := &Validation{}
+	v.Required("CustomerIds", len(r.CustomerIDs) > 0)
+	v.MaxItems("CustomerIds", len(r.CustomerIDs), 2)
+	v.Interval("UpdatedUtc", r.Start, r.End, MaxIntervalMonths)
+	return v.Err()
+}
+
+func TestValidate(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		req    *validatedRequest
+		fields []string
+	}{
+		{&validatedRequest{CustomerIDs: []string{"1"}, Start: start, End: start.AddDate(0, 3, 0)}, nil},
+		{&validatedRequest{}, []string{"CustomerIds"}},
+		{&validatedRequest{CustomerIDs: []string{"1", "2", "3"}}, []string{"CustomerIds"}},
+		{&validatedRequest{CustomerIDs: []string{"1"}, Start: start, End: start.AddDate(0, 3, 1)}, []string{"UpdatedUtc"}},
+		{&validatedRequest{CustomerIDs: []string{"1"}, Start: start, End: start.AddDate(0, 0, -1)}, []string{"UpdatedUtc"}},
+		{&validatedRequest{CustomerIDs: []string{"1"}, Limitation: Limitation{Count: 1001}}, []string{"Limitation.Count"}},
+	}
+
+	for i, test := range tests {
+		err := Validate(test.req)
+		if test.fields == nil {
+			if err != nil {
+				t.Errorf("%d: unexpected error %v", i, err)
+			}
+			continue
+		}
+
+		var rerr *InvalidRequestError
+		if !errors.As(err, &rerr) || !errors.Is(err, ErrValidation) {
+			t.Errorf("%d: unexpected error %v", i, err)
+			continue
+		}
+		if len(rerr.Fields) != len(test.fields) || rerr.Fields[0].Field != test.fields[0] {
+			t.Errorf("%d: fields %v, expected %v", i, rerr.Fields, test.fields)
+		}
+	}
+}
+
+func TestNewRequestValidates(t *testing.T) {
+	c := NewClient(nil, "access", "client")
+	c.BaseURL, _ = url.Parse("https://api.mews.test/api/connector/v1/")
+	apiURL, _ := c.GetApiURL("customers/getAll")
+
+	_, err := c.NewRequestWithContext(context.Background(), apiURL, &validatedRequest{})
+	if !errors.Is(err, ErrValidation) {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	c.SkipValidation = true
+	_, err = c.NewRequestWithContext(context.Background(), apiURL, &validatedRequest{})
+	if err != nil {
+		t.Error(err)
+	}
+}
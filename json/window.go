@@ -0,0 +1,49 @@
+package json
+
+import (
+	"context"
+	"time"
+)
+
+// Window is a part of an interval filter, see Windows.
+type Window struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Windows splits the interval from start to end into consecutive windows of
+// at most months months, the longest ones the API accepts. The windows share
+// their bounds, so an entity at a bound can be returned for two windows.
+// Windows returns nil for an interval without a start or end or that ends
+// before it starts.
+func Windows(start time.Time, end time.Time, months int) []Window {
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return nil
+	}
+	if months <= 0 {
+		return []Window{{Start: start, End: end}}
+	}
+
+	windows := []Window{}
+	for {
+		next := start.AddDate(0, months, 0)
+		if !next.Before(end) {
+			return append(windows, Window{Start: start, End: end})
+		}
+		windows = append(windows, Window{Start: start, End: next})
+		start = next
+	}
+}
+
+// FetchWindows calls fetch for every window and merges the responses with
+// MergeResponses, in the order of the windows. Entities returned for more than
+// one window are merged by their ID. The windows are fetched one at a time,
+// unless the call allows more with WithWindowConcurrency. The first error
+// cancels the windows that are still being fetched.
+func FetchWindows[Resp any](ctx context.Context, windows []Window, fetch func(ctx context.Context, w Window) (*Resp, error), opts ...RequestOption) (*Resp, error) {
+	concurrency := OptionsFromContext(WithOptions(ctx, opts...)).WindowConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	return fetchParts(ctx, windows, concurrency, fetch)
+}
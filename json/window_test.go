@@ -0,0 +1,65 @@
+package json
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	windows := Windows(start, start.AddDate(1, 0, 0), 3)
+	if len(windows) != 4 {
+		t.Fatalf("len(windows) = %d, expected 4", len(windows))
+	}
+	for i, w := range windows {
+		if !w.Start.Equal(start.AddDate(0, 3*i, 0)) || !w.End.Equal(start.AddDate(0, 3*i+3, 0)) {
+			t.Errorf("windows[%d] = %v - %v", i, w.Start, w.End)
+		}
+	}
+
+	windows = Windows(start, start.AddDate(0, 3, 1), 3)
+	if len(windows) != 2 || !windows[1].End.Equal(start.AddDate(0, 3, 1)) {
+		t.Errorf("windows = %v, expected a short last window", windows)
+	}
+
+	if windows := Windows(start, start.AddDate(0, 3, 0), 3); len(windows) != 1 {
+		t.Errorf("len(windows) = %d, expected 1 for a legal interval", len(windows))
+	}
+	if windows := Windows(start, start.AddDate(0, -1, 0), 3); windows != nil {
+		t.Errorf("windows = %v, expected none for an interval that ends before it starts", windows)
+	}
+}
+
+func TestFetchWindows(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	windows := Windows(start, start.AddDate(1, 0, 0), 3)
+
+	running, maxRunning := 0, 0
+	fetch := func(ctx context.Context, w Window) (*testChunkResponse, error) {
+		running++
+		maxRunning = max(maxRunning, running)
+		defer func() { running-- }()
+
+		// an entity at the start of every window is also returned for the
+		// window before it
+		return &testChunkResponse{Items: []testChunkItem{
+			{ID: w.Start.Format(time.DateOnly)},
+			{ID: w.End.Format(time.DateOnly)},
+		}}, nil
+	}
+
+	resp, err := FetchWindows(context.Background(), windows, fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxRunning != 1 {
+		t.Errorf("%d windows fetched at the same time, expected 1", maxRunning)
+	}
+	if len(resp.Items) != 5 {
+		t.Errorf("len(Items) = %d, expected 5", len(resp.Items))
+	}
+	if resp.Items[0].ID != "2024-01-01" || resp.Items[4].ID != "2025-01-01" {
+		t.Errorf("Items = %v, expected them in window order", resp.Items)
+	}
+}
@@ -0,0 +1,68 @@
+package mewstest
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	mews "github.com/omniboost/go-mews"
+)
+
+const (
+	// Tokens used in ModeReplay, they are scrubbed before matching anyway
+	ReplayAccessToken = "mewstest-access-token"
+	ReplayClientToken = "mewstest-client-token"
+)
+
+// CassettePath returns the path of the cassette for name in the testdata
+// directory of the package under test.
+func CassettePath(name string) string {
+	return filepath.Join("testdata", "cassettes", name+".jsonl")
+}
+
+// NewClient returns a client backed by the cassette for name.
+//
+// In ModeReplay (the default) no network access happens and the test is
+// skipped when the cassette doesn't exist. In ModeRecord (MEWS_RECORD=1) the
+// requests go to the API using MEWS_ACCESS_TOKEN, MEWS_CLIENT_TOKEN and
+// optionally MEWS_BASE_URL, and the cassette is written when the test ends.
+func NewClient(t testing.TB, name string) *mews.Client {
+	t.Helper()
+
+	mode := ModeFromEnv()
+	path := CassettePath(name)
+
+	recorder, err := NewRecorder(path, mode, nil)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Skipf("no cassette at %s, record it with MEWS_RECORD=1", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken := ReplayAccessToken
+	clientToken := ReplayClientToken
+	if mode == ModeRecord {
+		accessToken = os.Getenv("MEWS_ACCESS_TOKEN")
+		clientToken = os.Getenv("MEWS_CLIENT_TOKEN")
+		t.Cleanup(func() {
+			if err := recorder.Save(); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+
+	client := mews.NewClient(&http.Client{Transport: recorder}, accessToken, clientToken)
+	if baseURL := os.Getenv("MEWS_BASE_URL"); baseURL != "" && mode == ModeRecord {
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		client.SetBaseURL(u)
+	}
+
+	return client
+}
@@ -0,0 +1,622 @@
+package mewstest
+
+import (
+	gojson "encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omniboost/go-mews/accountingcategories"
+	"github.com/omniboost/go-mews/accountingitems"
+	"github.com/omniboost/go-mews/bills"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/ledgerentries"
+	"github.com/omniboost/go-mews/orderitems"
+	"github.com/omniboost/go-mews/payments"
+	"github.com/omniboost/go-mews/rates"
+	"github.com/omniboost/go-mews/reservations"
+	"github.com/omniboost/go-mews/resources"
+	"github.com/omniboost/go-mews/services"
+)
+
+const (
+	// check in and check out time of generated reservations, in UTC
+	checkInHour  = 14
+	checkOutHour = 10
+	// longest time between booking and arrival of generated reservations
+	maxLeadDays = 90
+
+	accommodationTaxRate = 0.09
+	accommodationTaxCode = "NL-2019-L"
+)
+
+var (
+	firstNames    = []string{"Emma", "Liam", "Olivia", "Noah", "Sophie", "Lucas", "Mila", "Daan", "Julia", "Sem", "Anna", "Finn", "Laura", "Jan", "Eva", "Max"}
+	lastNames     = []string{"de Jong", "Jansen", "de Vries", "van den Berg", "Bakker", "Visser", "Smit", "Meijer", "Mulder", "de Boer", "Dekker", "Brouwer", "Müller", "Schmidt", "Smith", "Martin"}
+	nationalities = []string{"NL", "NL", "NL", "DE", "BE", "GB", "FR", "US"}
+	origins       = []string{"Connector", "Distributor", "Channel", "Commander"}
+)
+
+// DatasetOptions configure a generated dataset. Zero values are replaced by
+// the defaults.
+type DatasetOptions struct {
+	// Seed of the generator, the same options always generate the same dataset
+	Seed uint64
+	// Now is the date the dataset is generated around, 2024-06-01 by default
+	Now time.Time
+	// Days is the number of days before and after Now that reservations span
+	Days int
+	// Number of customers and reservations
+	Customers    int
+	Reservations int
+	// Number of resource categories and of resources in each of them
+	ResourceCategories   int
+	ResourcesPerCategory int
+	// Currency of all amounts, EUR by default
+	Currency string
+}
+
+func (o DatasetOptions) withDefaults() DatasetOptions {
+	if o.Now.IsZero() {
+		o.Now = time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	}
+	if o.Days == 0 {
+		o.Days = 60
+	}
+	if o.Customers == 0 {
+		o.Customers = 200
+	}
+	if o.Reservations == 0 {
+		o.Reservations = 300
+	}
+	if o.ResourceCategories == 0 {
+		o.ResourceCategories = 3
+	}
+	if o.ResourcesPerCategory == 0 {
+		o.ResourcesPerCategory = 10
+	}
+	if o.Currency == "" {
+		o.Currency = "EUR"
+	}
+	return o
+}
+
+// Dataset is a synthetic, coherent property expressed in the types of this
+// library: reservations belong to customers, rates and resources, order items
+// to reservations, and past stays have closed bills that are paid in full.
+type Dataset struct {
+	Configuration               configuration.GetResponse
+	AccountingCategories        []accountingcategories.AccountingCategory
+	Services                    services.Services
+	ResourceCategories          resources.ResourceCategories
+	Resources                   resources.Resources
+	ResourceCategoryAssignments resources.ResourceCategoryAssignments
+	Rates                       rates.Rates
+	Customers                   customers.Customers
+	Reservations                reservations.Reservations
+	OrderItems                  orderitems.OrderItems
+	Payments                    payments.Payments
+	Bills                       bills.Bills
+	LedgerEntries               ledgerentries.LedgerEntries
+}
+
+// generator holds the state of a single GenerateDataset call.
+type generator struct {
+	opts DatasetOptions
+	rnd  *rand.Rand
+	d    *Dataset
+
+	accommodationCategoryID string
+	paymentCategoryID       string
+	// nightly price per resource category and rate
+	prices map[string]float64
+}
+
+// GenerateDataset generates a property with opts.
+func GenerateDataset(opts DatasetOptions) *Dataset {
+	opts = opts.withDefaults()
+	g := &generator{
+		opts:   opts,
+		rnd:    rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x6d657773)),
+		d:      &Dataset{},
+		prices: map[string]float64{},
+	}
+
+	g.enterprise()
+	g.accountingCategories()
+	g.service()
+	g.resources()
+	g.rates()
+	g.customers()
+	g.reservations()
+	return g.d
+}
+
+func (g *generator) id() string {
+	return fmt.Sprintf("%08x-%04x-4%03x-8%03x-%012x",
+		g.rnd.Uint32(), g.rnd.Uint32()&0xffff, g.rnd.Uint32()&0xfff, g.rnd.Uint32()&0xfff, g.rnd.Uint64()&0xffffffffffff)
+}
+
+func (g *generator) pick(values []string) string {
+	return values[g.rnd.IntN(len(values))]
+}
+
+// day returns the start of the day n days from Now.
+func (g *generator) day(n int) time.Time {
+	now := g.opts.Now.UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+n, 0, 0, 0, 0, time.UTC)
+}
+
+func (g *generator) enterprise() {
+	created := g.day(-3 * 365)
+	g.d.Configuration = configuration.GetResponse{
+		NowUtc: g.opts.Now,
+		Enterprise: configuration.Enterprise{
+			ID:                  g.id(),
+			Name:                "Hotel " + g.pick(lastNames),
+			CreatedUTC:          created,
+			UpdatedUTC:          created,
+			TimeZoneIdentifier:  "Europe/Amsterdam",
+			DefaultLanguageCode: "en-US",
+			Email:               "info@hotel.example",
+			Address: configuration.Address{
+				Line1:       "Damrak 1",
+				City:        "Amsterdam",
+				PostalCode:  "1012 LG",
+				CountryCode: "NL",
+			},
+			Currencies: configuration.Currencies{
+				{Currency: g.opts.Currency, IsDefault: true, IsEnabled: true},
+			},
+		},
+	}
+}
+
+func (g *generator) accountingCategories() {
+	for _, c := range []struct{ name, code string }{
+		{"Accommodation", "ACC"},
+		{"Food and beverage", "FB"},
+		{"Payments", "PAY"},
+	} {
+		g.d.AccountingCategories = append(g.d.AccountingCategories, accountingcategories.AccountingCategory{
+			ID:       g.id(),
+			IsActive: true,
+			Name:     c.name,
+			Code:     c.code,
+		})
+	}
+	g.accommodationCategoryID = g.d.AccountingCategories[0].ID
+	g.paymentCategoryID = g.d.AccountingCategories[2].ID
+}
+
+func (g *generator) service() {
+	value, _ := gojson.Marshal(services.BookableServiceData{
+		StartOffset:    json.Duration{Hours: checkInHour},
+		EndOffset:      json.Duration{Hours: checkOutHour},
+		TimeUnitPeriod: "Day",
+	})
+	g.d.Services = services.Services{{
+		ID:        g.id(),
+		IsActive:  true,
+		Name:      "Accommodation",
+		StartTime: fmt.Sprintf("PT%dH", checkInHour),
+		EndTime:   fmt.Sprintf("PT%dH", checkOutHour),
+		Type:      services.ServiceReservable,
+		Data: services.ServiceData{
+			Discriminator: "Bookable",
+			Value:         value,
+		},
+	}}
+}
+
+func (g *generator) resources() {
+	created := g.d.Configuration.Enterprise.CreatedUTC
+	names := []string{"Standard Room", "Superior Room", "Deluxe Room", "Junior Suite", "Suite"}
+
+	for c := 0; c < g.opts.ResourceCategories; c++ {
+		name := names[c%len(names)]
+		if c >= len(names) {
+			name += " " + strconv.Itoa(c/len(names)+1)
+		}
+		category := resources.ResourceCategory{
+			ID:           g.id(),
+			EnterpriseID: g.d.Configuration.Enterprise.ID,
+			ServiceID:    g.d.Services[0].ID,
+			IsActive:     true,
+			Type:         "Room",
+			Names:        configuration.LocalizedText{"en-US": name},
+			ShortNames:   configuration.LocalizedText{"en-US": strings.ToUpper(name[:3])},
+			Ordering:     c,
+			Capacity:     2 + c/2,
+		}
+		g.d.ResourceCategories = append(g.d.ResourceCategories, category)
+
+		for r := 0; r < g.opts.ResourcesPerCategory; r++ {
+			resource := resources.Resource{
+				ID:         g.id(),
+				IsActive:   true,
+				Name:       strconv.Itoa((c+1)*100 + r + 1),
+				State:      "Clean",
+				CreatedUTC: created,
+				UpdatedUTC: created,
+			}
+			g.d.Resources = append(g.d.Resources, resource)
+			g.d.ResourceCategoryAssignments = append(g.d.ResourceCategoryAssignments, resources.ResourceCategoryAssignment{
+				ID:         g.id(),
+				IsActive:   true,
+				CategoryID: category.ID,
+				ResourceID: resource.ID,
+				CreatedUTC: created,
+				UpdatedUTC: created,
+			})
+		}
+	}
+}
+
+func (g *generator) rates() {
+	base := rates.Rate{
+		ID:        g.id(),
+		GroupID:   g.id(),
+		ServiceID: g.d.Services[0].ID,
+		IsActive:  true,
+		IsEnabled: true,
+		IsPublic:  true,
+		Name:      "Best Available Rate",
+		ShortName: "BAR",
+	}
+	nonRefundable := base
+	nonRefundable.ID = g.id()
+	nonRefundable.BaseRateID = base.ID
+	nonRefundable.Name = "Non Refundable"
+	nonRefundable.ShortName = "NRF"
+	g.d.Rates = rates.Rates{base, nonRefundable}
+
+	for i, c := range g.d.ResourceCategories {
+		price := float64(90 + 40*i + g.rnd.IntN(20))
+		g.prices[c.ID+base.ID] = price
+		g.prices[c.ID+nonRefundable.ID] = math.Round(price * 0.9)
+	}
+}
+
+func (g *generator) customers() {
+	for i := 0; i < g.opts.Customers; i++ {
+		first := g.pick(firstNames)
+		last := g.pick(lastNames)
+		// before the first reservation can have been booked
+		created := g.day(-g.opts.Days - maxLeadDays - 1 - g.rnd.IntN(365)).Add(time.Duration(g.rnd.IntN(86400)) * time.Second)
+		email := strings.ToLower(strings.ReplaceAll(fmt.Sprintf("%s.%s.%d@example.com", first, last, i+1), " ", ""))
+
+		g.d.Customers = append(g.d.Customers, customers.Customer{
+			ID:              g.id(),
+			Number:          strconv.Itoa(i + 1),
+			FirstName:       first,
+			LastName:        last,
+			NationalityCode: g.pick(nationalities),
+			LanguageCode:    "en-US",
+			Email:           email,
+			CreatedUTC:      created,
+			UpdatedUTC:      created,
+			IsActive:        true,
+		})
+	}
+}
+
+func (g *generator) reservations() {
+	now := g.opts.Now
+	groupID := ""
+
+	for i := 0; i < g.opts.Reservations; i++ {
+		customer := g.d.Customers[g.rnd.IntN(len(g.d.Customers))]
+		c := g.rnd.IntN(len(g.d.ResourceCategories))
+		category := g.d.ResourceCategories[c]
+		resource := g.d.Resources[c*g.opts.ResourcesPerCategory+g.rnd.IntN(g.opts.ResourcesPerCategory)]
+		rate := g.d.Rates[g.rnd.IntN(len(g.d.Rates))]
+
+		nights := 1 + g.rnd.IntN(7)
+		arrival := g.day(g.rnd.IntN(2*g.opts.Days) - g.opts.Days)
+		start := arrival.Add(checkInHour * time.Hour)
+		end := arrival.AddDate(0, 0, nights).Add(checkOutHour * time.Hour)
+
+		created := start.Add(-time.Duration(1+g.rnd.IntN(maxLeadDays*24)) * time.Hour)
+		if created.After(now) {
+			created = now.Add(-time.Duration(1+g.rnd.IntN(24)) * time.Hour)
+		}
+
+		// some consecutive reservations share a group
+		if i == 0 || g.rnd.IntN(4) != 0 {
+			groupID = g.id()
+		}
+
+		r := reservations.Reservation{
+			ID:                  g.id(),
+			ServiceID:           g.d.Services[0].ID,
+			GroupID:             groupID,
+			Number:              strconv.Itoa(1000 + i),
+			Origin:              g.pick(origins),
+			CreatedUTC:          created,
+			UpdatedUTC:          created,
+			StartUTC:            start,
+			EndUTC:              end,
+			RequestedCategoryID: category.ID,
+			AssignedResourceID:  resource.ID,
+			RateID:              rate.ID,
+			AdultCount:          1 + g.rnd.IntN(category.Capacity),
+			CustomerID:          customer.ID,
+		}
+
+		switch {
+		case g.rnd.IntN(10) == 0:
+			r.State = reservations.ReservationStateCanceled
+			r.CancelledUTC = created.Add(time.Duration(g.rnd.Int64N(int64(cancelBefore(start, now).Sub(created)) + 1)))
+			r.UpdatedUTC = r.CancelledUTC
+			r.CancellationReason = "Other"
+		case !end.After(now):
+			r.State = reservations.ReservationStateProcessed
+			r.UpdatedUTC = end
+		case !start.After(now):
+			r.State = reservations.ReservationStateStarted
+			r.UpdatedUTC = start
+		default:
+			r.State = reservations.ReservationStateConfirmed
+		}
+
+		g.d.Reservations = append(g.d.Reservations, r)
+		g.orderItems(r, nights, g.prices[category.ID+rate.ID])
+	}
+}
+
+// orderItems adds the nights of r and, when r is processed, its payment,
+// bill and ledger entries.
+func (g *generator) orderItems(r reservations.Reservation, nights int, price float64) {
+	enterpriseID := g.d.Configuration.Enterprise.ID
+	closed := r.State == reservations.ReservationStateProcessed
+	billID := ""
+	if closed {
+		billID = g.id()
+	}
+
+	if r.State == reservations.ReservationStateCanceled {
+		nights = 0
+	}
+
+	bill := bills.Bill{
+		ID:         billID,
+		CustomerID: r.CustomerID,
+		State:      bills.BillStateClosed,
+		Type:       bills.BillTypeReceipt,
+		IssuedUTC:  r.EndUTC,
+		OwnerData:  bills.BillOwnerData{Discriminator: "BillCustomerData"},
+	}
+
+	total := 0.0
+	for n := 0; n < nights; n++ {
+		consumed := r.StartUTC.AddDate(0, 0, n)
+		net, tax := g.net(price, accommodationTaxRate)
+		item := orderitems.OrderItem{
+			ID:                   g.id(),
+			EnterpriseID:         enterpriseID,
+			AccountID:            r.CustomerID,
+			AccountType:          "Customer",
+			ServiceID:            r.ServiceID,
+			ServiceOrderID:       r.ID,
+			AccountingCategoryID: g.accommodationCategoryID,
+			BillingName:          "Accommodation",
+			UnitCount:            1,
+			UnitAmount:           g.orderItemAmount(price, net, tax),
+			Amount:               g.orderItemAmount(price, net, tax),
+			OriginalAmount:       g.orderItemAmount(price, net, tax),
+			RevenueType:          orderitems.RevenueTypeService,
+			ConsumedUTC:          consumed,
+			CreatedUTC:           r.CreatedUTC,
+			UpdatedUTC:           r.UpdatedUTC,
+			StartUTC:             consumed,
+			AccountingState:      orderitems.AccountingStateOpen,
+			Type:                 orderitems.OrderItemTypeSpaceOrder,
+		}
+		if closed {
+			item.BillID = billID
+			item.ClosedUTC = r.EndUTC
+			item.AccountingState = orderitems.AccountingStateClosed
+		}
+		g.d.OrderItems = append(g.d.OrderItems, item)
+		total += price
+
+		if closed {
+			bill.Revenue = append(bill.Revenue, accountingitems.AccountingItem{
+				ID:                   item.ID,
+				CustomerID:           r.CustomerID,
+				ServiceID:            r.ServiceID,
+				OrderID:              r.ID,
+				BillID:               billID,
+				AccountingCategoryID: item.AccountingCategoryID,
+				Amount:               g.billAmount(price, net, tax),
+				Type:                 accountingitems.ServiceRenue,
+				Name:                 item.BillingName,
+				ConsumptionUTC:       consumed,
+			})
+			g.ledgerEntry(ledgerentries.LedgerTypeRevenue, item.ID, "Revenue", r.CustomerID, billID, item.AccountingCategoryID, consumed, -net)
+			g.ledgerEntry(ledgerentries.LedgerTypeTax, item.ID, "Revenue", r.CustomerID, billID, item.AccountingCategoryID, consumed, -tax)
+		}
+	}
+
+	if !closed {
+		return
+	}
+
+	payment := payments.Payment{
+		ID:                   g.id(),
+		EnterpriseID:         enterpriseID,
+		AccountID:            r.CustomerID,
+		AccountType:          "Customer",
+		BillID:               billID,
+		ReservationID:        r.ID,
+		AccountingCategoryID: g.paymentCategoryID,
+		Amount:               payments.Amount{Currency: g.opts.Currency, NetValue: total, GrossValue: total, Value: total},
+		OriginalAmount:       payments.Amount{Currency: g.opts.Currency, NetValue: total, GrossValue: total, Value: total},
+		ConsumedUTC:          r.EndUTC,
+		ClosedUTC:            r.EndUTC,
+		ChargedUTC:           r.EndUTC,
+		CreatedUTC:           r.EndUTC,
+		UpdatedUTC:           r.EndUTC,
+		AccountingState:      payments.AccountingStateClosed,
+		State:                payments.PaymentStateCharged,
+		Type:                 payments.PaymentTypeCreditCardPayment,
+	}
+	if g.rnd.IntN(3) == 0 {
+		payment.Type = payments.PaymentTypeCashPayment
+	}
+	g.d.Payments = append(g.d.Payments, payment)
+	g.ledgerEntry(ledgerentries.LedgerTypePayment, payment.ID, "Payment", r.CustomerID, billID, payment.AccountingCategoryID, r.EndUTC, total)
+
+	bill.Number = strconv.Itoa(len(g.d.Bills) + 1)
+	bill.Payments = bills.Payments{{
+		ID:                   payment.ID,
+		CustomerID:           r.CustomerID,
+		BillID:               billID,
+		AccountingCategoryID: payment.AccountingCategoryID,
+		Amount:               accountingitems.Amount{Currency: g.opts.Currency, NetValue: -total, GrossValue: -total, Value: -total},
+		Type:                 accountingitems.Payment,
+		Name:                 string(payment.Type),
+		ConsumptionUTC:       r.EndUTC,
+	}}
+	g.d.Bills = append(g.d.Bills, bill)
+}
+
+// ledgerEntry adds an entry of value to the ledger of ledgerType: credits are
+// negative, debits positive. Tax entries are all accommodation tax.
+func (g *generator) ledgerEntry(ledgerType ledgerentries.LedgerType, itemID, itemType, accountID, billID, categoryID string, posted time.Time, value float64) {
+	entryType := "Debit"
+	if value < 0 {
+		entryType = "Credit"
+	}
+	var taxRateCode any
+	if ledgerType == ledgerentries.LedgerTypeTax {
+		taxRateCode = accommodationTaxCode
+	}
+
+	g.d.LedgerEntries = append(g.d.LedgerEntries, ledgerentries.LedgerEntry{
+		ID:                   g.id(),
+		EnterpriseID:         g.d.Configuration.Enterprise.ID,
+		TransactionID:        g.id(),
+		AccountID:            accountID,
+		BillID:               billID,
+		AccountingCategoryID: categoryID,
+		AccountingItemID:     itemID,
+		AccountingItemType:   itemType,
+		LedgerType:           string(ledgerType),
+		LedgerEntryType:      entryType,
+		PostingDate:          posted.Format("2006-01-02"),
+		Value:                round(value),
+		TaxRateCode:          taxRateCode,
+		CreatedUTC:           posted,
+	})
+}
+
+// net returns the net value and tax of a gross amount.
+func (g *generator) net(gross, rate float64) (float64, float64) {
+	net := round(gross / (1 + rate))
+	return net, round(gross - net)
+}
+
+func (g *generator) orderItemAmount(gross, net, tax float64) orderitems.Amount {
+	return orderitems.Amount{
+		Currency:   g.opts.Currency,
+		NetValue:   net,
+		GrossValue: gross,
+		TaxValues:  orderitems.TaxValues{{Code: accommodationTaxCode, Value: tax}},
+	}
+}
+
+func (g *generator) billAmount(gross, net, tax float64) accountingitems.Amount {
+	return accountingitems.Amount{
+		Currency:   g.opts.Currency,
+		NetValue:   net,
+		GrossValue: gross,
+		TaxValues:  accountingitems.TaxValues{{Code: accommodationTaxCode, Value: tax}},
+		Value:      gross,
+	}
+}
+
+// cancelBefore returns the latest time a reservation starting at start can
+// have been canceled.
+func cancelBefore(start, now time.Time) time.Time {
+	if start.Before(now) {
+		return start
+	}
+	return now
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Load replaces the data of store with the dataset.
+func (d *Dataset) Load(store *Store) {
+	store.Lock()
+	defer store.Unlock()
+
+	store.Configuration = d.Configuration
+	store.AccountingCategories = append([]accountingcategories.AccountingCategory{}, d.AccountingCategories...)
+	store.Services = append([]services.Service{}, d.Services...)
+	store.ResourceCategories = append([]resources.ResourceCategory{}, d.ResourceCategories...)
+	store.Resources = append([]resources.Resource{}, d.Resources...)
+	store.ResourceCategoryAssignments = append([]resources.ResourceCategoryAssignment{}, d.ResourceCategoryAssignments...)
+	store.Rates = append([]rates.Rate{}, d.Rates...)
+	store.Customers = append([]customers.Customer{}, d.Customers...)
+	store.Reservations = append([]reservations.Reservation{}, d.Reservations...)
+	store.Bills = append([]bills.Bill{}, d.Bills...)
+	store.OrderItems = append([]orderitems.OrderItem{}, d.OrderItems...)
+	store.Payments = append([]payments.Payment{}, d.Payments...)
+	store.LedgerEntries = append([]ledgerentries.LedgerEntry{}, d.LedgerEntries...)
+}
+
+// Responses returns the dataset as the responses of the endpoints that return
+// it, each response holding all items in a single page.
+func (d *Dataset) Responses() map[string]interface{} {
+	return map[string]interface{}{
+		"configuration/get":           d.Configuration,
+		"accountingCategories/getAll": accountingcategories.AllResponse{AccountingCategories: d.AccountingCategories},
+		"services/getAll":             services.AllResponse{Services: d.Services},
+		"resources/getAll": resources.AllResponse{
+			Resources:                   d.Resources,
+			ResourceCategories:          d.ResourceCategories,
+			ResourceCategoryAssignments: d.ResourceCategoryAssignments,
+		},
+		"rates/getAll":         rates.AllResponse{Rates: d.Rates},
+		"customers/getAll":     customers.AllResponse{Customers: d.Customers},
+		"reservations/getAll":  reservations.AllResponse{Reservations: d.Reservations},
+		"orderItems/getAll":    orderitems.AllResponse{OrderItems: d.OrderItems},
+		"payments/getAll":      payments.AllResponse{Payments: d.Payments},
+		"bills/getAll":         bills.AllResponse{Bills: d.Bills},
+		"ledgerEntries/getAll": ledgerentries.AllResponse{LedgerEntries: d.LedgerEntries},
+	}
+}
+
+// WriteFiles writes the responses of the dataset as JSON files in dir, one per
+// endpoint, e.g. dir/customers/getAll.json.
+func (d *Dataset) WriteFiles(dir string) error {
+	for endpoint, resp := range d.Responses() {
+		b, err := gojson.MarshalIndent(resp, "", "  ")
+		if err != nil {
+			return fmt.Errorf("mewstest: %s: %w", endpoint, err)
+		}
+
+		path := filepath.Join(dir, filepath.FromSlash(endpoint)+".json")
+		err = os.MkdirAll(filepath.Dir(path), 0o755)
+		if err != nil {
+			return err
+		}
+		err = os.WriteFile(path, b, 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package mewstest
+
+import (
+	gojson "encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/ledgerentries"
+	"github.com/omniboost/go-mews/reservations"
+)
+
+func TestGenerateDatasetIsDeterministic(t *testing.T) {
+	a, _ := gojson.Marshal(GenerateDataset(DatasetOptions{Seed: 1}))
+	b, _ := gojson.Marshal(GenerateDataset(DatasetOptions{Seed: 1}))
+	c, _ := gojson.Marshal(GenerateDataset(DatasetOptions{Seed: 2}))
+
+	if string(a) != string(b) {
+		t.Error("the same seed generated different datasets")
+	}
+	if string(a) == string(c) {
+		t.Error("different seeds generated the same dataset")
+	}
+}
+
+func TestGenerateDatasetIsCoherent(t *testing.T) {
+	d := GenerateDataset(DatasetOptions{Seed: 1, Customers: 20, Reservations: 100})
+
+	customerIDs := map[string]bool{}
+	for _, c := range d.Customers {
+		customerIDs[c.ID] = true
+	}
+	states := map[reservations.ReservationState]int{}
+	for _, r := range d.Reservations {
+		states[r.State]++
+		if !customerIDs[r.CustomerID] {
+			t.Errorf("reservation %s has unknown customer %s", r.ID, r.CustomerID)
+		}
+		for _, c := range d.Customers {
+			if c.ID == r.CustomerID && !c.CreatedUTC.Before(r.CreatedUTC) {
+				t.Errorf("reservation %s was created before its customer", r.ID)
+			}
+		}
+		if !r.StartUTC.Before(r.EndUTC) {
+			t.Errorf("reservation %s ends before it starts", r.ID)
+		}
+	}
+	for _, state := range []reservations.ReservationState{
+		reservations.ReservationStateProcessed,
+		reservations.ReservationStateConfirmed,
+		reservations.ReservationStateCanceled,
+	} {
+		if states[state] == 0 {
+			t.Errorf("no %s reservations in %v", state, states)
+		}
+	}
+
+	if len(d.Bills) != states[reservations.ReservationStateProcessed] {
+		t.Errorf("got %d bills for %d processed reservations", len(d.Bills), states[reservations.ReservationStateProcessed])
+	}
+	paymentIDs := map[string]bool{}
+	for _, p := range d.Payments {
+		paymentIDs[p.ID] = true
+	}
+	ledger := 0.0
+	for _, e := range d.LedgerEntries {
+		ledger += e.Value
+		if paymentIDs[e.AccountingItemID] != (e.LedgerType == string(ledgerentries.LedgerTypePayment)) {
+			t.Errorf("ledger entry %s of %s is in the %s ledger", e.ID, e.AccountingItemType, e.LedgerType)
+		}
+	}
+	if round(ledger) != 0 {
+		t.Errorf("the ledger has a balance of %v", ledger)
+	}
+
+	for _, b := range d.Bills {
+		balance := 0.0
+		for _, i := range b.Revenue {
+			balance += i.Amount.GrossValue
+		}
+		for _, i := range b.Payments {
+			balance += i.Amount.GrossValue
+		}
+		if round(balance) != 0 {
+			t.Errorf("bill %s has a balance of %v", b.ID, balance)
+		}
+	}
+}
+
+func TestDatasetWriteFiles(t *testing.T) {
+	dir := t.TempDir()
+	d := GenerateDataset(DatasetOptions{Seed: 1, Customers: 5, Reservations: 10})
+	if err := d.WriteFiles(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "customers", "getAll.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp := customers.AllResponse{}
+	if err := gojson.Unmarshal(b, &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(resp.Customers, d.Customers) {
+		t.Error("customers don't survive a round trip through JSON")
+	}
+}
+
+func TestDatasetLoad(t *testing.T) {
+	d := GenerateDataset(DatasetOptions{Seed: 1, Customers: 20, Reservations: 50})
+	server := newTestServer(t)
+	d.Load(server.Store)
+
+	client := server.NewClient()
+	items, err := json.CollectAll(client.OrderItems.AllIter(client.OrderItems.NewAllRequest(), json.WithPageSize(100)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != len(d.OrderItems) {
+		t.Errorf("got %d order items, expected %d", len(items), len(d.OrderItems))
+	}
+
+	svcs, err := json.CollectAll(client.Services.AllIter(client.Services.NewAllRequest()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(svcs) != len(d.Services) {
+		t.Errorf("got %d services, expected %d", len(svcs), len(d.Services))
+	}
+
+	rateReq := client.Rates.NewAllRequest()
+	rateReq.ServiceIDs = []string{d.Services[0].ID}
+	rts, err := json.CollectAll(client.Rates.AllIter(rateReq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rts) != len(d.Rates) {
+		t.Errorf("got %d rates, expected %d", len(rts), len(d.Rates))
+	}
+
+	resourceReq := client.Resources.NewAllRequest()
+	resourceReq.Extent.Resources = true
+	resourceReq.Extent.ResourceCategories = true
+	resourceReq.Extent.ResourceCategoryAssignments = true
+	res, err := client.Resources.All(resourceReq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Resources) != len(d.Resources) || len(res.ResourceCategories) != len(d.ResourceCategories) ||
+		len(res.ResourceCategoryAssignments) != len(d.ResourceCategoryAssignments) {
+		t.Errorf("got %d resources, %d categories and %d assignments, expected %d, %d and %d",
+			len(res.Resources), len(res.ResourceCategories), len(res.ResourceCategoryAssignments),
+			len(d.Resources), len(d.ResourceCategories), len(d.ResourceCategoryAssignments))
+	}
+
+	entries, err := json.CollectAll(client.LedgerEntries.AllIter(client.LedgerEntries.NewAllRequest()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != len(d.LedgerEntries) {
+		t.Errorf("got %d ledger entries, expected %d", len(entries), len(d.LedgerEntries))
+	}
+
+	ledgerReq := client.LedgerEntries.NewAllRequest()
+	ledgerReq.LedgerTypes = []ledgerentries.LedgerType{ledgerentries.LedgerTypePayment}
+	entries, err = json.CollectAll(client.LedgerEntries.AllIter(ledgerReq))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || len(entries) != len(d.Payments) {
+		t.Errorf("got %d payment ledger entries, expected %d", len(entries), len(d.Payments))
+	}
+}
@@ -0,0 +1,915 @@
+package mewstest
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/omniboost/go-mews/accountingcategories"
+	"github.com/omniboost/go-mews/agecategories"
+	"github.com/omniboost/go-mews/bills"
+	"github.com/omniboost/go-mews/commands"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/enterprises"
+	"github.com/omniboost/go-mews/ledgerentries"
+	"github.com/omniboost/go-mews/orderitems"
+	"github.com/omniboost/go-mews/payments"
+	"github.com/omniboost/go-mews/rates"
+	"github.com/omniboost/go-mews/reservations"
+	"github.com/omniboost/go-mews/resources"
+	"github.com/omniboost/go-mews/services"
+)
+
+// Filters on data the library types don't carry (e.g. the creation time of a
+// bill) are validated but not applied.
+func (s *Server) routes() {
+	s.handlers["configuration/get"] = s.configurationGet
+	s.handlers["enterprises/getAll"] = s.enterprisesGetAll
+	s.handlers["accountingCategories/getAll"] = s.accountingCategoriesGetAll
+	s.handlers["services/getAll"] = s.servicesGetAll
+	s.handlers["resources/getAll"] = s.resourcesGetAll
+	s.handlers["rates/getAll"] = s.ratesGetAll
+	s.handlers["customers/getAll"] = s.customersGetAll
+	s.handlers["customers/add"] = s.customersAdd
+	s.handlers["customers/update"] = s.customersUpdate
+	s.handlers["reservations/getAll"] = s.reservationsGetAll
+	s.handlers["reservations/getAll/2023-06-06"] = s.reservationsGetAll20230606
+	s.handlers["reservations/add"] = s.reservationsAdd
+	s.handlers["reservations/update"] = s.reservationsUpdate
+	s.handlers["bills/getAll"] = s.billsGetAll
+	s.handlers["bills/getAllByIds"] = s.billsGetAllByIDs
+	s.handlers["orderItems/getAll"] = s.orderItemsGetAll
+	s.handlers["payments/getAll"] = s.paymentsGetAll
+	s.handlers["payments/addExternal"] = s.paymentsAddExternal
+	s.handlers["ledgerEntries/getAll"] = s.ledgerEntriesGetAll
+	s.handlers["commands/getAllActive"] = s.commandsGetAllActive
+	s.handlers["commands/getAllByIDs"] = s.commandsGetAllByIDs
+	s.handlers["commands/update"] = s.commandsUpdate
+}
+
+func (s *Server) configurationGet(body []byte) (interface{}, error) {
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	resp := s.Store.Configuration
+	resp.NowUtc = s.Store.now()
+	return resp, nil
+}
+
+func (s *Server) enterprisesGetAll(body []byte) (interface{}, error) {
+	req := enterprises.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"LinkedUtc":  req.LinkedUTC,
+		"UpdatedUtc": req.UpdatedUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := s.Store.Enterprises
+	if len(items) == 0 {
+		items = []configuration.Enterprise{s.Store.Configuration.Enterprise}
+	}
+	items = filter(items, func(e configuration.Enterprise) bool {
+		return matches(req.EnterpriseIDs, e.ID) &&
+			matches(req.ExternalIdentifiers, e.ExternalIdentifier) &&
+			inInterval(e.LinkedUTC, req.LinkedUTC) &&
+			inInterval(e.UpdatedUTC, req.UpdatedUTC)
+	})
+	items, cursor, err := page(items, req.Limitation, func(e configuration.Enterprise) string { return e.ID })
+	if err != nil {
+		return nil, err
+	}
+	return enterprises.AllResponse{Enterprises: items, Cursor: cursor}, nil
+}
+
+func (s *Server) accountingCategoriesGetAll(body []byte) (interface{}, error) {
+	req := accountingcategories.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.UpdatedUTC != nil {
+		if err := s.validateIntervals(map[string]configuration.TimeInterval{"UpdatedUtc": *req.UpdatedUTC}); err != nil {
+			return nil, err
+		}
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.AccountingCategories, func(c accountingcategories.AccountingCategory) bool {
+		return matches(req.AccountingCategoryIDs, c.ID) &&
+			isInActivityStates(c.IsActive, req.ActivityStates)
+	})
+	items, cursor, err := page(items, *req.GetLimitation(), func(c accountingcategories.AccountingCategory) string { return c.ID })
+	if err != nil {
+		return nil, err
+	}
+	return accountingcategories.AllResponse{AccountingCategories: items, Cursor: cursor}, nil
+}
+
+func (s *Server) servicesGetAll(body []byte) (interface{}, error) {
+	req := services.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Services, func(svc services.Service) bool {
+		return matches(req.ServiceIDs, svc.ID)
+	})
+	items, cursor, err := page(items, req.Limitation, func(svc services.Service) string { return svc.ID })
+	if err != nil {
+		return nil, err
+	}
+	return services.AllResponse{Services: items, Cursor: cursor}, nil
+}
+
+// resourcesGetAll returns the collections selected by the extent, only the
+// resources when it selects none.
+func (s *Server) resourcesGetAll(body []byte) (interface{}, error) {
+	req := resources.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"CreatedUtc": req.CreatedUTC,
+		"UpdatedUtc": req.UpdatedUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	extent := req.Extent
+	if !extent.Resources && !extent.ResourceCategories && !extent.ResourceCategoryAssignments {
+		extent.Resources = true
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	resp := resources.AllResponse{}
+	if extent.Resources {
+		resp.Resources = filter(s.Store.Resources, func(r resources.Resource) bool {
+			return matches(req.ResourceIDs, r.ID) &&
+				(r.IsActive || extent.Inactive) &&
+				inInterval(r.CreatedUTC, req.CreatedUTC) &&
+				inInterval(r.UpdatedUTC, req.UpdatedUTC)
+		})
+	}
+	if extent.ResourceCategories {
+		resp.ResourceCategories = filter(s.Store.ResourceCategories, func(c resources.ResourceCategory) bool {
+			return c.IsActive || extent.Inactive
+		})
+	}
+	if extent.ResourceCategoryAssignments {
+		resp.ResourceCategoryAssignments = filter(s.Store.ResourceCategoryAssignments, func(a resources.ResourceCategoryAssignment) bool {
+			return matches(req.ResourceIDs, a.ResourceID) && (a.IsActive || extent.Inactive)
+		})
+	}
+	return resp, nil
+}
+
+func (s *Server) ratesGetAll(body []byte) (interface{}, error) {
+	req := rates.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Rates, func(r rates.Rate) bool {
+		return matches(req.ServiceIDs, r.ServiceID)
+	})
+	items, cursor, err := page(items, req.Limitation, func(r rates.Rate) string { return r.ID })
+	if err != nil {
+		return nil, err
+	}
+	return rates.AllResponse{Rates: items, Cursor: cursor}, nil
+}
+
+func (s *Server) customersGetAll(body []byte) (interface{}, error) {
+	req := customers.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"CreatedUtc": req.CreatedUTC,
+		"UpdatedUtc": req.UpdatedUTC,
+		"DeletedUtc": req.DeletedUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activityStates := make([]string, len(req.ActivityStates))
+	for i, state := range req.ActivityStates {
+		activityStates[i] = string(state)
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Customers, func(c customers.Customer) bool {
+		return matches(req.CustomerIDs, c.ID) &&
+			matches(req.CompanyIDs, c.CompanyID) &&
+			matches(req.Emails, c.Email) &&
+			matches(req.FirstNames, c.FirstName) &&
+			matches(req.LastNames, c.LastName) &&
+			matches(req.LoyaltyCodes, c.LoyaltyCode) &&
+			inInterval(c.CreatedUTC, req.CreatedUTC) &&
+			inInterval(c.UpdatedUTC, req.UpdatedUTC) &&
+			isDeletedIn(c, req.DeletedUTC) &&
+			isInActivityStates(c.IsActive, activityStates)
+	})
+	items, cursor, err := page(items, req.Limitation, func(c customers.Customer) string { return c.ID })
+	if err != nil {
+		return nil, err
+	}
+	return customers.AllResponse{Customers: items, Cursor: cursor}, nil
+}
+
+func (s *Server) customersAdd(body []byte) (interface{}, error) {
+	req := customers.AddRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.LastName == "" {
+		return nil, badRequest("LastName is required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	now := s.Store.now()
+	c := customers.Customer{
+		ID:         s.Store.NewID(),
+		Number:     strconv.Itoa(len(s.Store.Customers) + 1),
+		CreatedUTC: now,
+		IsActive:   true,
+	}
+	i := len(s.Store.Customers)
+	for n, existing := range s.Store.Customers {
+		if req.Email == "" || !strings.EqualFold(existing.Email, req.Email) {
+			continue
+		}
+		if !req.OverwriteExisting {
+			return nil, badRequest("Customer with email %s already exists.", req.Email)
+		}
+		c, i = existing, n
+	}
+
+	c.FirstName = req.FirstName
+	c.LastName = req.LastName
+	c.SecondLastName = req.SecondLastName
+	c.Title = customers.Title(req.Title)
+	c.Sex = customers.Sex(req.Sex)
+	c.NationalityCode = req.NationalityCode
+	if req.BirthDate != nil {
+		c.BirthDate = req.BirthDate.Format("2006-01-02")
+	}
+	c.BirthPlace = req.BirthPlace
+	c.Email = req.Email
+	c.Phone = req.Phone
+	c.LoyaltyCode = req.LoyaltyCode
+	c.Notes = req.Notes
+	if req.Address != nil {
+		c.Address = *req.Address
+	}
+	c.ItalianDestinationCode = req.ItalianDestinationCode
+	c.UpdatedUTC = now
+
+	if i == len(s.Store.Customers) {
+		s.Store.Customers = append(s.Store.Customers, c)
+	} else {
+		s.Store.Customers[i] = c
+	}
+	return customers.AddResponse(c), nil
+}
+
+// customersUpdate leaves empty values unchanged, the request type can't
+// express null.
+func (s *Server) customersUpdate(body []byte) (interface{}, error) {
+	req := customers.UpdateRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	i, ok := s.Store.customer(req.CustomerID)
+	if !ok {
+		return nil, badRequest("Customer %s not found.", req.CustomerID)
+	}
+	c := &s.Store.Customers[i]
+
+	update(&c.FirstName, req.FirstName)
+	update(&c.LastName, req.LastName)
+	update(&c.SecondLastName, req.SecondLastName)
+	update(&c.Title, customers.Title(req.Title))
+	if req.BirthDate != nil && !req.BirthDate.IsZero() {
+		c.BirthDate = req.BirthDate.Format("2006-01-02")
+	}
+	update(&c.BirthPlace, req.BirthPlace)
+	update(&c.NationalityCode, req.NationalityCode)
+	update(&c.Email, req.Email)
+	update(&c.Phone, req.Phone)
+	update(&c.LoyaltyCode, req.LoyaltyCode)
+	update(&c.Notes, req.Notes)
+	if req.Address != nil {
+		c.Address = *req.Address
+	}
+	if req.Classifications != nil {
+		c.Classifications = req.Classifications
+	}
+	if req.Options != nil {
+		c.Options = *req.Options
+	}
+	c.UpdatedUTC = s.Store.now()
+
+	return customers.UpdateResponse(*c), nil
+}
+
+func (s *Server) reservationsGetAll(body []byte) (interface{}, error) {
+	req := reservations.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	interval := configuration.TimeInterval{}
+	if req.StartUTC != nil {
+		interval.StartUTC = *req.StartUTC
+	}
+	if req.EndUTC != nil {
+		interval.EndUTC = *req.EndUTC
+	}
+	if err := s.validateIntervals(map[string]configuration.TimeInterval{"StartUtc/EndUtc": interval}); err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Reservations, func(r reservations.Reservation) bool {
+		if !matches(req.ServiceIDs, r.ServiceID) ||
+			!matches(req.CustomerIDs, r.CustomerID) ||
+			!matches(req.States, r.State) {
+			return false
+		}
+
+		switch req.TimeFilter {
+		case reservations.ReservationTimeFilterCreated:
+			return inInterval(r.CreatedUTC, interval)
+		case reservations.ReservationTimeFilterUpdated:
+			return inInterval(r.UpdatedUTC, interval)
+		case reservations.ReservationTimeFilterStart:
+			return inInterval(r.StartUTC, interval)
+		case reservations.ReservationTimeFilterEnd:
+			return inInterval(r.EndUTC, interval)
+		case reservations.ReservationTimeFilterCancelled:
+			return inInterval(r.CancelledUTC, interval)
+		default:
+			return collides(r.StartUTC, r.EndUTC, interval)
+		}
+	})
+	items, cursor, err := page(items, req.Limitation, func(r reservations.Reservation) string { return r.ID })
+	if err != nil {
+		return nil, err
+	}
+	return reservations.AllResponse{Reservations: items, Cursor: cursor}, nil
+}
+
+func (s *Server) reservationsGetAll20230606(body []byte) (interface{}, error) {
+	req := reservations.GetAll20230606Request{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"CreatedUtc":        req.CreatedUTC,
+		"UpdatedUtc":        req.UpdatedUTC,
+		"ScheduledStartUtc": req.ScheduledStartUTC,
+		"ScheduledEndUtc":   req.ScheduledEndUTC,
+		"ActualStartUtc":    req.ActualStartUTC,
+		"ActualEndUtc":      req.ActualEndUTC,
+		"CollidingUtc":      req.CollidingUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Reservations, func(r reservations.Reservation) bool {
+		return matches(req.ReservationIDs, r.ID) &&
+			matches(req.ServiceIDs, r.ServiceID) &&
+			matches(req.AccountIDs, r.CustomerID) &&
+			matches(req.ReservationGroupIDs, r.GroupID) &&
+			matches(req.AssignedResourceIds, r.AssignedResourceID) &&
+			matches(req.States, reservations.ReservationStates(r.State)) &&
+			inInterval(r.CreatedUTC, req.CreatedUTC) &&
+			inInterval(r.UpdatedUTC, req.UpdatedUTC) &&
+			inInterval(r.StartUTC, req.ScheduledStartUTC) &&
+			inInterval(r.EndUTC, req.ScheduledEndUTC) &&
+			collides(r.StartUTC, r.EndUTC, req.CollidingUTC)
+	})
+	items, cursor, err := page(items, req.Limitation, func(r reservations.Reservation) string { return r.ID })
+	if err != nil {
+		return nil, err
+	}
+
+	resp := reservations.AllResponse20230606{Reservations: reservations.Reservations20230606{}, Cursor: cursor}
+	for _, r := range items {
+		resp.Reservations = append(resp.Reservations, s.reservation20230606(r))
+	}
+	return resp, nil
+}
+
+func (s *Server) reservation20230606(r reservations.Reservation) reservations.Reservation20230606 {
+	return reservations.Reservation20230606{
+		ID:                          r.ID,
+		EnterpriseID:                s.Store.Configuration.Enterprise.ID,
+		ServiceID:                   r.ServiceID,
+		AccountID:                   r.CustomerID,
+		AccountType:                 "Customer",
+		BookerID:                    r.BookerID,
+		StartUTC:                    r.StartUTC,
+		EndUTC:                      r.EndUTC,
+		Number:                      r.Number,
+		State:                       reservations.ReservationStates(r.State),
+		Origin:                      r.Origin,
+		CreatedUTC:                  r.CreatedUTC,
+		UpdatedUTC:                  r.UpdatedUTC,
+		ReleasedUTC:                 r.ReleasedUTC,
+		CancelledUTC:                r.CancelledUTC,
+		BusinessSegmentID:           r.BusinessSegmentID,
+		RateID:                      r.RateID,
+		GroupID:                     r.GroupID,
+		RequestedResourceCategoryID: r.RequestedCategoryID,
+		AssignedResourceID:          r.AssignedResourceID,
+		CompanyID:                   r.CompanyID,
+		TravelAgencyID:              r.TravelAgencyID,
+		AssignedResourceLocked:      r.AssignedResourceLocked,
+		ChannelNumber:               r.ChannelNumber,
+		ChannelManagerNumber:        r.ChannelManagerNumber,
+		CancellationReason:          r.CancellationReason,
+	}
+}
+
+type addedReservation struct {
+	Identifier  string
+	Reservation reservations.Reservation
+}
+
+func (s *Server) reservationsAdd(body []byte) (interface{}, error) {
+	req := reservations.AddRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.ServiceID == "" {
+		return nil, badRequest("ServiceId is required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	groupID := req.GroupID
+	if groupID == "" {
+		groupID = s.Store.NewID()
+	}
+
+	now := s.Store.now()
+	added := []addedReservation{}
+	for _, r := range req.Reservations {
+		if _, ok := s.Store.customer(r.CustomerID); !ok {
+			return nil, badRequest("Customer %s not found.", r.CustomerID)
+		}
+		if !r.StartUtc.Before(r.EndUtc) {
+			return nil, badRequest("EndUtc must be after StartUtc.")
+		}
+
+		state := reservations.ReservationState(r.State)
+		if state == "" {
+			state = reservations.ReservationStateConfirmed
+		}
+		adults, children := 0, 0
+		for _, c := range r.PersonCounts {
+			i, ok := s.Store.ageCategory(c.AgeCategoryID)
+			if !ok {
+				return nil, badRequest("Age category %s not found.", c.AgeCategoryID)
+			}
+			if s.Store.AgeCategories[i].Classification == agecategories.AgeCategoryClassificationAdult {
+				adults += c.Count
+			} else {
+				children += c.Count
+			}
+		}
+
+		added = append(added, addedReservation{
+			Identifier: r.Identifier,
+			Reservation: reservations.Reservation{
+				ID:                  s.Store.NewID(),
+				ServiceID:           req.ServiceID,
+				GroupID:             groupID,
+				Number:              strconv.Itoa(len(s.Store.Reservations) + len(added) + 1),
+				ChannelNumber:       r.ChannelNumber,
+				State:               state,
+				Origin:              r.Origin,
+				CreatedUTC:          now,
+				UpdatedUTC:          now,
+				StartUTC:            r.StartUtc,
+				EndUTC:              r.EndUtc,
+				RequestedCategoryID: r.RequestedCategoryID,
+				BusinessSegmentID:   r.BusinessSegmentID,
+				CompanyID:           r.CompanyID,
+				TravelAgencyID:      r.TravelAgencyID,
+				RateID:              r.RateID,
+				AdultCount:          adults,
+				ChildCount:          children,
+				CustomerID:          r.CustomerID,
+				BookerID:            r.BookerID,
+			},
+		})
+	}
+
+	for _, a := range added {
+		s.Store.Reservations = append(s.Store.Reservations, a.Reservation)
+	}
+	return struct{ Reservations []addedReservation }{added}, nil
+}
+
+func (s *Server) reservationsUpdate(body []byte) (interface{}, error) {
+	req := reservations.UpdateRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	// validate everything before changing anything
+	updated := reservations.Reservations{}
+	for _, u := range req.ReservationUpdates {
+		i, ok := s.Store.reservation(u.ReservationID)
+		if !ok {
+			return nil, badRequest("Reservation %s not found.", u.ReservationID)
+		}
+		r := s.Store.Reservations[i]
+
+		if err := updateTime(&r.StartUTC, u.StartUTC); err != nil {
+			return nil, err
+		}
+		if err := updateTime(&r.EndUTC, u.EndUTC); err != nil {
+			return nil, err
+		}
+		if !r.StartUTC.Before(r.EndUTC) {
+			return nil, badRequest("EndUtc must be after StartUtc.")
+		}
+		updateValue(&r.AssignedResourceID, u.AssignedResourceID)
+		updateValue(&r.ChannelNumber, u.ChannelNumber)
+		updateValue(&r.RequestedCategoryID, u.RequestedCategoryID)
+		updateValue(&r.TravelAgencyID, u.TravelAgencyID)
+		updateValue(&r.CompanyID, u.CompanyID)
+		updateValue(&r.BusinessSegmentID, u.BusinessSegmentID)
+		updateValue(&r.RateID, u.RateID)
+		updateValue(&r.BookerID, u.BookerID)
+		if u.AssignedResourceLocked != nil && u.AssignedResourceLocked.Value != nil {
+			r.AssignedResourceLocked = *u.AssignedResourceLocked.Value
+		}
+		r.UpdatedUTC = s.Store.now()
+
+		updated = append(updated, r)
+	}
+
+	for _, r := range updated {
+		i, _ := s.Store.reservation(r.ID)
+		s.Store.Reservations[i] = r
+	}
+	return reservations.UpdateResponse{Reservations: updated}, nil
+}
+
+func (s *Server) billsGetAll(body []byte) (interface{}, error) {
+	req := bills.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"IssuedUtc":  req.IssuedUTC,
+		"PaidUtc":    req.PaidUTC,
+		"DueUtc":     req.DueUTC,
+		"CreatedUtc": req.CreatedUTC,
+		"UpdatedUtc": req.UpdatedUTC,
+		"ClosedUtc":  req.ClosedUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Bills, func(b bills.Bill) bool {
+		due := time.Time{}
+		if b.DueUTC != nil {
+			due = *b.DueUTC
+		}
+		return matches(req.BillIDs, b.ID) &&
+			matches(req.CustomerIDs, b.CustomerID) &&
+			(req.State == "" || strings.EqualFold(string(req.State), string(b.State))) &&
+			(req.Type == "" || req.Type == b.Type) &&
+			inInterval(b.IssuedUTC, req.IssuedUTC) &&
+			inInterval(due, req.DueUTC)
+	})
+	items, cursor, err := page(items, req.Limitation, func(b bills.Bill) string { return b.ID })
+	if err != nil {
+		return nil, err
+	}
+	return bills.AllResponse{Bills: items, Cursor: cursor}, nil
+}
+
+func (s *Server) billsGetAllByIDs(body []byte) (interface{}, error) {
+	req := bills.AllByIDsRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if len(req.BillIDs) == 0 {
+		return nil, badRequest("BillIds are required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Bills, func(b bills.Bill) bool {
+		return matches(req.BillIDs, b.ID)
+	})
+	return bills.AllByIDsResponse{Bills: items}, nil
+}
+
+func (s *Server) orderItemsGetAll(body []byte) (interface{}, error) {
+	req := orderitems.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"CreatedUtc":  req.CreatedUTC,
+		"UpdatedUtc":  req.UpdatedUTC,
+		"ConsumedUtc": req.ConsumedUTC,
+		"CanceledUtc": req.CanceledUTC,
+		"ClosedUtc":   req.ClosedUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.OrderItems, func(o orderitems.OrderItem) bool {
+		return matches(req.EnterpriseIDs, o.EnterpriseID) &&
+			matches(req.OrderItemIDs, o.ID) &&
+			matches(req.ServiceOrderIDs, o.ServiceOrderID) &&
+			matches(req.ServiceIDs, o.ServiceID) &&
+			matches(req.BillIDs, o.BillID) &&
+			matches(req.AccountingStates, o.AccountingState) &&
+			(req.Currency == "" || req.Currency == o.Amount.Currency) &&
+			inInterval(o.CreatedUTC, req.CreatedUTC) &&
+			inInterval(o.UpdatedUTC, req.UpdatedUTC) &&
+			inInterval(o.ConsumedUTC, req.ConsumedUTC) &&
+			inInterval(o.ClosedUTC, req.ClosedUTC)
+	})
+	items, cursor, err := page(items, req.Limitation, func(o orderitems.OrderItem) string { return o.ID })
+	if err != nil {
+		return nil, err
+	}
+	return orderitems.AllResponse{OrderItems: items, Cursor: cursor}, nil
+}
+
+func (s *Server) paymentsGetAll(body []byte) (interface{}, error) {
+	req := payments.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	err := s.validateIntervals(map[string]configuration.TimeInterval{
+		"CreatedUtc":    req.CreatedUTC,
+		"UpdatedUtc":    req.UpdatedUTC,
+		"ChargedUtc":    req.ChargedUTC,
+		"ClosedUtc":     req.ClosedUTC,
+		"SettlementUtc": req.SettlementUTC,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Payments, func(p payments.Payment) bool {
+		return matches(req.EnterpriseIDs, p.EnterpriseID) &&
+			matches(req.PaymentIDs, p.ID) &&
+			matches(req.BillIDs, p.BillID) &&
+			matches(req.AccountingStates, p.AccountingState) &&
+			matches(req.States, p.State) &&
+			(req.Type == "" || req.Type == p.Type) &&
+			(req.Currency == "" || req.Currency == p.Amount.Currency) &&
+			inInterval(p.CreatedUTC, req.CreatedUTC) &&
+			inInterval(p.UpdatedUTC, req.UpdatedUTC) &&
+			inInterval(p.ChargedUTC, req.ChargedUTC) &&
+			inInterval(p.ClosedUTC, req.ClosedUTC) &&
+			inInterval(p.SettlementUTC, req.SettlementUTC)
+	})
+	items, cursor, err := page(items, req.Limitation, func(p payments.Payment) string { return p.ID })
+	if err != nil {
+		return nil, err
+	}
+	return payments.AllResponse{Payments: items, Cursor: cursor}, nil
+}
+
+func (s *Server) paymentsAddExternal(body []byte) (interface{}, error) {
+	req := payments.AddExternalRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.AccountID == "" {
+		return nil, badRequest("AccountId is required.")
+	}
+	if req.Amount.Currency == "" {
+		return nil, badRequest("Amount Currency is required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	now := s.Store.now()
+	p := payments.Payment{
+		ID:                   s.Store.NewID(),
+		EnterpriseID:         s.Store.Configuration.Enterprise.ID,
+		AccountID:            req.AccountID,
+		AccountType:          "Customer",
+		BillID:               req.BillID,
+		ReservationID:        req.ReservationID,
+		AccountingCategoryID: req.AccountingCategoryID,
+		Amount: payments.Amount{
+			Currency:   req.Amount.Currency,
+			NetValue:   req.Amount.NetValue,
+			GrossValue: req.Amount.GrossValue,
+			Value:      req.Amount.Value,
+		},
+		Notes:           req.Notes,
+		ChargedUTC:      now,
+		CreatedUTC:      now,
+		UpdatedUTC:      now,
+		AccountingState: payments.AccountingStateOpen,
+		State:           payments.PaymentStateCharged,
+		Identifier:      req.ExternalIdentifier,
+		Type:            payments.PaymentTypeExternalPayment,
+	}
+	s.Store.Payments = append(s.Store.Payments, p)
+	return payments.AddExternalResponse{ExternalPaymentID: p.ID}, nil
+}
+
+func (s *Server) ledgerEntriesGetAll(body []byte) (interface{}, error) {
+	req := ledgerentries.AllRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+
+	// posting dates are compared as dates, in the format of the entries
+	start, end := "", ""
+	if !req.PostingDate.Start.IsZero() {
+		start = req.PostingDate.Start.Format("2006-01-02")
+	}
+	if !req.PostingDate.End.IsZero() {
+		end = req.PostingDate.End.Format("2006-01-02")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.LedgerEntries, func(e ledgerentries.LedgerEntry) bool {
+		return matches(req.EnterpriseIDs, e.EnterpriseID) &&
+			matches(req.LedgerTypes, ledgerentries.LedgerType(e.LedgerType)) &&
+			(start == "" || e.PostingDate >= start) &&
+			(end == "" || e.PostingDate <= end)
+	})
+	items, cursor, err := page(items, req.Limitation, func(e ledgerentries.LedgerEntry) string { return e.ID })
+	if err != nil {
+		return nil, err
+	}
+	return ledgerentries.AllResponse{LedgerEntries: items, Cursor: cursor}, nil
+}
+
+func (s *Server) commandsGetAllActive(body []byte) (interface{}, error) {
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Commands, func(c commands.Command) bool {
+		return c.State == commands.CommandStatePending ||
+			c.State == commands.CommandStateReceived ||
+			c.State == commands.CommandStateProcessing
+	})
+	return commands.AllActiveResponse{Commands: items}, nil
+}
+
+func (s *Server) commandsGetAllByIDs(body []byte) (interface{}, error) {
+	req := commands.AllByIDsRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if len(req.CommandIDs) == 0 {
+		return nil, badRequest("CommandIds are required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	items := filter(s.Store.Commands, func(c commands.Command) bool {
+		return matches(req.CommandIDs, c.ID)
+	})
+	return commands.AllByIDsResponse{Commands: items}, nil
+}
+
+func (s *Server) commandsUpdate(body []byte) (interface{}, error) {
+	req := commands.UpdateRequest{}
+	if err := decode(body, &req); err != nil {
+		return nil, err
+	}
+	if req.State == "" {
+		return nil, badRequest("State is required.")
+	}
+
+	s.Store.Lock()
+	defer s.Store.Unlock()
+
+	i, ok := s.Store.command(req.CommandID)
+	if !ok {
+		return nil, badRequest("Command %s not found.", req.CommandID)
+	}
+	s.Store.Commands[i].State = req.State
+	return commands.UpdateResponse{}, nil
+}
+
+// isInActivityStates reports whether a record is in one of states, all records
+// are when states is empty.
+func isInActivityStates(active bool, states []string) bool {
+	if len(states) == 0 {
+		return true
+	}
+	state := string(services.ActivityStateDeleted)
+	if active {
+		state = string(services.ActivityStateActive)
+	}
+	return matches(states, state)
+}
+
+// isDeletedIn reports whether c was deleted in i. Customers have no deletion
+// time, a deleted customer is inactive and was last updated when deleted.
+func isDeletedIn(c customers.Customer, i configuration.TimeInterval) bool {
+	if i.IsEmpty() {
+		return true
+	}
+	return !c.IsActive && inInterval(c.UpdatedUTC, i)
+}
+
+// collides reports whether a stay from start to end overlaps i.
+func collides(start, end time.Time, i configuration.TimeInterval) bool {
+	if i.IsEmpty() {
+		return true
+	}
+	return start.Before(i.EndUTC) && end.After(i.StartUTC)
+}
+
+func update[T comparable](dst *T, v T) {
+	var zero T
+	if v != zero {
+		*dst = v
+	}
+}
+
+func updateValue(dst *string, v *reservations.StringUpdateValue) {
+	if v != nil && v.Value != nil {
+		*dst = *v.Value
+	}
+}
+
+func updateTime(dst *time.Time, v *reservations.StringUpdateValue) error {
+	if v == nil || v.Value == nil {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, *v.Value)
+	if err != nil {
+		return badRequest("Invalid time %s.", *v.Value)
+	}
+	*dst = t.UTC()
+	return nil
+}
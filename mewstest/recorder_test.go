@@ -0,0 +1,96 @@
+package mewstest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func post(t *testing.T, client *http.Client, url string, body string) (int, string) {
+	t.Helper()
+
+	resp, err := client.Post(url, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(b)
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Request-Id", "req-1")
+		w.Write([]byte(`{"Customers":[{"Id":"c1"}],"Cursor":"c1"}`))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.jsonl")
+	url := server.URL + "/api/connector/v1/customers/getAll"
+
+	// record
+	recorder, err := NewRecorder(path, ModeRecord, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	post(t, &http.Client{Transport: recorder}, url, `{"ClientToken":"secret-client","AccessToken":"secret-access","Limitation":{"Count":10}}`)
+	if err := recorder.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	cassette, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(cassette), "secret") {
+		t.Errorf("cassette %s contains a token", cassette)
+	}
+
+	// replay, with other tokens and another key order
+	server.Close()
+	recorder, err = NewRecorder(path, ModeReplay, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recorder}
+	status, body := post(t, client, url, `{"Limitation":{"Count":10},"AccessToken":"other","ClientToken":"other"}`)
+	if status != http.StatusOK || body != `{"Cursor":"c1","Customers":[{"Id":"c1"}]}` {
+		t.Errorf("replayed %d %s", status, body)
+	}
+
+	// the interaction has been used and the body differs
+	_, err = client.Post(url, "application/json", strings.NewReader(`{"Limitation":{"Count":20}}`))
+	if !errors.Is(err, ErrNoInteraction) {
+		t.Errorf("err = %v, expected %v", err, ErrNoInteraction)
+	}
+}
+
+func TestReplayMissingCassette(t *testing.T) {
+	_, err := NewRecorder(filepath.Join(t.TempDir(), "missing.jsonl"), ModeReplay, nil)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("err = %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/connector/v1/customers/getAll":               "customers/getAll",
+		"/api/connector/v1/reservations/getAll/2023-06-06": "reservations/getAll/2023-06-06",
+		"/customers/getAll":                                "customers/getAll",
+	}
+
+	for path, expected := range tests {
+		if got := Endpoint(path); got != expected {
+			t.Errorf("Endpoint(%q) = %q, expected %q", path, got, expected)
+		}
+	}
+}
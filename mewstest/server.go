@@ -0,0 +1,320 @@
+package mewstest
+
+import (
+	"compress/gzip"
+	gojson "encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mews "github.com/omniboost/go-mews"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/json"
+)
+
+const (
+	// Tokens accepted by a Server returned by NewServer
+	ServerAccessToken = "mewstest-server-access-token"
+	ServerClientToken = "mewstest-server-client-token"
+
+	// MaxLimitationCount is the largest page size the server accepts
+	MaxLimitationCount = 1000
+)
+
+// HandlerFunc handles a request to an endpoint of a fake Server. body is the
+// JSON request body, its tokens have already been validated. The returned
+// value is sent as JSON, an *Error is sent as an error response with its
+// status code and any other error as a 500.
+type HandlerFunc func(body []byte) (interface{}, error)
+
+// Error is an error response of a fake Server.
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s", e.Status, e.Message)
+}
+
+func badRequest(format string, args ...interface{}) *Error {
+	return &Error{Status: http.StatusBadRequest, Message: fmt.Sprintf(format, args...)}
+}
+
+// Fault is an error response the server sends instead of handling a request.
+type Fault struct {
+	Status int
+	// Sent in the Retry-After header when set
+	RetryAfter time.Duration
+	Message    string
+}
+
+// Server is an in-process fake of the Connector API backed by a Store. It
+// validates tokens, pages with Limitation and Cursor, applies the common
+// filters of the endpoints it implements and can be told to fail requests.
+//
+// Endpoints that aren't implemented respond with 404, more can be added with
+// Handle.
+type Server struct {
+	*httptest.Server
+
+	Store       *Store
+	AccessToken string
+	ClientToken string
+	// MaxInterval is the longest time interval filter that is accepted, there
+	// is no limit when it is zero.
+	MaxInterval time.Duration
+
+	mu        sync.Mutex
+	handlers  map[string]HandlerFunc
+	faults    map[string][]Fault
+	requests  map[string]int
+	requestID int
+}
+
+// NewServer starts a fake server with an empty store. It must be closed when
+// done, e.g. with t.Cleanup(server.Close).
+func NewServer() *Server {
+	s := &Server{
+		Store:       NewStore(),
+		AccessToken: ServerAccessToken,
+		ClientToken: ServerClientToken,
+		handlers:    map[string]HandlerFunc{},
+		faults:      map[string][]Fault{},
+		requests:    map[string]int{},
+	}
+	s.routes()
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+// BaseURL returns the URL to use as the base URL of a client.
+func (s *Server) BaseURL() *url.URL {
+	u, _ := url.Parse(s.URL + "/api/connector/v1/")
+	return u
+}
+
+// NewClient returns a client that talks to the server with its tokens.
+func (s *Server) NewClient() *mews.Client {
+	client := mews.NewClient(s.Client(), s.AccessToken, s.ClientToken)
+	client.SetBaseURL(s.BaseURL())
+	return client
+}
+
+// Handle registers h for endpoint, e.g. "customers/getAll", replacing the
+// built in handler if there is one.
+func (s *Server) Handle(endpoint string, h HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[endpoint] = h
+}
+
+// Fail makes the next requests to endpoint fail with faults, one request per
+// fault.
+func (s *Server) Fail(endpoint string, faults ...Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults[endpoint] = append(s.faults[endpoint], faults...)
+}
+
+// Requests returns the number of requests that were made to endpoint,
+// including the ones that failed.
+func (s *Server) Requests(endpoint string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[endpoint]
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	endpoint := Endpoint(r.URL.Path)
+
+	s.mu.Lock()
+	s.requests[endpoint]++
+	s.requestID++
+	w.Header().Set("Request-Id", strconv.Itoa(s.requestID))
+	handler := s.handlers[endpoint]
+	var fault *Fault
+	if faults := s.faults[endpoint]; len(faults) > 0 {
+		fault = &faults[0]
+		s.faults[endpoint] = faults[1:]
+	}
+	s.mu.Unlock()
+
+	if fault != nil {
+		if fault.RetryAfter > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+		}
+		message := fault.Message
+		if message == "" {
+			message = http.StatusText(fault.Status)
+		}
+		writeError(w, &Error{Status: fault.Status, Message: message})
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		writeError(w, &Error{Status: http.StatusMethodNotAllowed, Message: "Only POST requests are supported."})
+		return
+	}
+	if handler == nil {
+		writeError(w, &Error{Status: http.StatusNotFound, Message: fmt.Sprintf("Endpoint %s not found.", endpoint)})
+		return
+	}
+
+	body, err := readBody(r)
+	if err != nil {
+		writeError(w, badRequest("Invalid request body."))
+		return
+	}
+
+	if err := s.authenticate(body); err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp, err := handler(body)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		gojson.NewEncoder(w).Encode(resp)
+		return
+	}
+	w.Header().Set("Content-Encoding", "gzip")
+	zw := gzip.NewWriter(w)
+	gojson.NewEncoder(zw).Encode(resp)
+	zw.Close()
+}
+
+// readBody reads the body of a request, which may be gzip compressed.
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return io.ReadAll(r.Body)
+	}
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(zr)
+}
+
+func (s *Server) authenticate(body []byte) error {
+	tokens := struct {
+		AccessToken string
+		ClientToken string
+	}{}
+	if err := decode(body, &tokens); err != nil {
+		return err
+	}
+
+	if tokens.ClientToken != s.ClientToken {
+		return &Error{Status: http.StatusUnauthorized, Message: "Invalid ClientToken."}
+	}
+	if tokens.AccessToken != s.AccessToken {
+		return &Error{Status: http.StatusUnauthorized, Message: "Invalid AccessToken."}
+	}
+	return nil
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	e := &Error{}
+	if !errors.As(err, &e) {
+		e = &Error{Status: http.StatusInternalServerError, Message: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(e.Status)
+	gojson.NewEncoder(w).Encode(struct {
+		Message   string
+		RequestId string
+	}{
+		Message:   e.Message,
+		RequestId: w.Header().Get("Request-Id"),
+	})
+}
+
+func decode(body []byte, v interface{}) error {
+	if err := gojson.Unmarshal(body, v); err != nil {
+		return badRequest("Invalid request body: %s", err)
+	}
+	return nil
+}
+
+// page returns the items after the cursor of limitation, at most Count of
+// them, and the cursor of the returned page.
+func page[T any](items []T, limitation json.Limitation, id func(T) string) ([]T, string, error) {
+	if limitation.Count <= 0 || limitation.Count > MaxLimitationCount {
+		return nil, "", badRequest("Limitation Count must be between 1 and %d.", MaxLimitationCount)
+	}
+
+	if limitation.Cursor != "" {
+		i := slices.IndexFunc(items, func(item T) bool {
+			return id(item) == limitation.Cursor
+		})
+		if i < 0 {
+			return nil, "", badRequest("Invalid Cursor %s.", limitation.Cursor)
+		}
+		items = items[i+1:]
+	}
+
+	items = items[:min(len(items), limitation.Count)]
+	if len(items) == 0 {
+		return items, "", nil
+	}
+	return items, id(items[len(items)-1]), nil
+}
+
+func filter[T any](items []T, keep func(T) bool) []T {
+	kept := []T{}
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// validateIntervals checks the time interval filters of a request.
+func (s *Server) validateIntervals(intervals map[string]configuration.TimeInterval) error {
+	for name, i := range intervals {
+		if i.IsEmpty() {
+			continue
+		}
+		if i.StartUTC.IsZero() || i.EndUTC.IsZero() {
+			return badRequest("%s must have both StartUtc and EndUtc.", name)
+		}
+		if i.EndUTC.Before(i.StartUTC) {
+			return badRequest("%s EndUtc must be after StartUtc.", name)
+		}
+		if s.MaxInterval > 0 && i.EndUTC.Sub(i.StartUTC) > s.MaxInterval {
+			return badRequest("%s exceeds the maximum length of %s.", name, s.MaxInterval)
+		}
+	}
+	return nil
+}
+
+// inInterval reports whether t is in the half open interval i. Every time is
+// in an empty interval.
+func inInterval(t time.Time, i configuration.TimeInterval) bool {
+	if i.IsEmpty() {
+		return true
+	}
+	return !t.Before(i.StartUTC) && t.Before(i.EndUTC)
+}
+
+// matches reports whether v is one of values. Every value matches when values
+// is empty.
+func matches[T comparable](values []T, v T) bool {
+	return len(values) == 0 || slices.Contains(values, v)
+}
@@ -0,0 +1,224 @@
+package mewstest
+
+import (
+	gojson "encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/omniboost/go-mews/agecategories"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/reservations"
+)
+
+func newTestServer(t *testing.T) *Server {
+	t.Helper()
+
+	server := NewServer()
+	t.Cleanup(server.Close)
+	server.Store.Now = func() time.Time {
+		return time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	}
+	return server
+}
+
+func TestServerPaging(t *testing.T) {
+	server := newTestServer(t)
+	for i := 0; i < 5; i++ {
+		server.Store.Customers = append(server.Store.Customers, customers.Customer{
+			ID:         server.Store.NewID(),
+			LastName:   "Doe",
+			UpdatedUTC: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC),
+		})
+	}
+
+	client := server.NewClient()
+	requestBody := client.Customers.NewAllRequest()
+	requestBody.UpdatedUTC = configuration.TimeInterval{
+		StartUTC: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		EndUTC:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	items, err := json.CollectAll(client.Customers.AllIter(requestBody, json.WithPageSize(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 4 {
+		t.Errorf("got %d customers, expected 4", len(items))
+	}
+	// the last page is empty
+	if n := server.Requests("customers/getAll"); n != 3 {
+		t.Errorf("made %d requests, expected 3", n)
+	}
+}
+
+func TestServerDeletedCustomers(t *testing.T) {
+	server := newTestServer(t)
+	for i := 0; i < 4; i++ {
+		server.Store.Customers = append(server.Store.Customers, customers.Customer{
+			ID:         server.Store.NewID(),
+			LastName:   "Doe",
+			UpdatedUTC: time.Date(2024, 1, 1+i, 0, 0, 0, 0, time.UTC),
+			IsActive:   i%2 == 0,
+		})
+	}
+
+	client := server.NewClient()
+	requestBody := client.Customers.NewAllRequest()
+	requestBody.DeletedUTC = configuration.TimeInterval{
+		StartUTC: time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+		EndUTC:   time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	items, err := json.CollectAll(client.Customers.AllIter(requestBody))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the first inactive customer was deleted before the interval
+	if len(items) != 1 || items[0].ID != server.Store.Customers[3].ID {
+		t.Errorf("got %+v", items)
+	}
+}
+
+func TestServerInvalidToken(t *testing.T) {
+	server := newTestServer(t)
+	client := server.NewClient()
+	server.AccessToken = "other"
+
+	_, err := client.Configuration.Get(client.Configuration.NewGetRequest())
+	if !errors.Is(err, json.ErrInvalidToken) {
+		t.Errorf("err = %v, expected %v", err, json.ErrInvalidToken)
+	}
+}
+
+func TestServerValidation(t *testing.T) {
+	server := newTestServer(t)
+	server.MaxInterval = 24 * time.Hour
+
+	client := server.NewClient()
+	requestBody := client.Customers.NewAllRequest()
+	requestBody.Limitation.Count = 10
+	requestBody.UpdatedUTC = configuration.TimeInterval{
+		StartUTC: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndUTC:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+	}
+	_, err := client.Customers.All(requestBody)
+	if !errors.Is(err, json.ErrValidation) {
+		t.Errorf("err = %v, expected %v", err, json.ErrValidation)
+	}
+
+	requestBody = client.Customers.NewAllRequest()
+	_, err = client.Customers.All(requestBody)
+	if !errors.Is(err, json.ErrValidation) {
+		t.Errorf("err without Limitation = %v, expected %v", err, json.ErrValidation)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	server := newTestServer(t)
+	server.Fail("configuration/get",
+		Fault{Status: http.StatusServiceUnavailable},
+		Fault{Status: http.StatusInternalServerError},
+		Fault{Status: http.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+	)
+
+	client := server.NewClient()
+	client.SetRetryPolicy(&json.BackoffRetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond})
+
+	// the first two attempts fail, no retries are left
+	_, err := client.Configuration.Get(client.Configuration.NewGetRequest())
+	if !errors.Is(err, json.ErrServer) {
+		t.Errorf("err = %v, expected %v", err, json.ErrServer)
+	}
+
+	client.SetRetryPolicy(nil)
+	_, err = client.Configuration.Get(client.Configuration.NewGetRequest())
+	throttled := &json.ThrottledError{}
+	if !errors.As(err, &throttled) || throttled.RetryAfter != 2*time.Second {
+		t.Errorf("err = %v, expected a throttled error with a retry after of 2s", err)
+	}
+
+	if n := server.Requests("configuration/get"); n != 3 {
+		t.Errorf("made %d requests, expected 3", n)
+	}
+}
+
+func TestServerReservations(t *testing.T) {
+	server := newTestServer(t)
+	server.Store.AgeCategories = []agecategories.AgeCategory{
+		{ID: "adult", Classification: agecategories.AgeCategoryClassificationAdult},
+		{ID: "child", Classification: agecategories.AgeCategoryClassificationChild},
+	}
+	client := server.NewClient()
+
+	addCustomer := client.Customers.NewAddRequest()
+	addCustomer.LastName = "Doe"
+	addCustomer.Email = "john@example.com"
+	customer, err := client.Customers.Add(addCustomer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Customers.Add(addCustomer)
+	if !errors.Is(err, json.ErrValidation) {
+		t.Errorf("adding a duplicate customer: err = %v, expected %v", err, json.ErrValidation)
+	}
+
+	start := time.Date(2024, 7, 1, 14, 0, 0, 0, time.UTC)
+	addReservation := client.Reservations.NewAddRequest()
+	addReservation.ServiceID = "service"
+	addReservation.Reservations = reservations.AddRequestReservations{{
+		Identifier: "1",
+		StartUtc:   start,
+		EndUtc:     start.Add(2 * 24 * time.Hour),
+		CustomerID: customer.ID,
+	}}
+	personCounts := &addReservation.Reservations[0].PersonCounts
+	err = gojson.Unmarshal([]byte(`[{"AgeCategoryId":"adult","Count":2},{"AgeCategoryId":"child","Count":1}]`), personCounts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	added, err := client.Reservations.Add(addReservation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(added.Reservations) != 1 || added.Reservations[0].Reservation.State != reservations.ReservationStateConfirmed {
+		t.Fatalf("added %+v", added.Reservations)
+	}
+	if r := added.Reservations[0].Reservation; r.AdultCount != 2 || r.ChildCount != 1 {
+		t.Errorf("added %d adults and %d children, expected 2 and 1", r.AdultCount, r.ChildCount)
+	}
+
+	(*personCounts)[0].AgeCategoryID = "unknown"
+	_, err = client.Reservations.Add(addReservation)
+	if !errors.Is(err, json.ErrValidation) {
+		t.Errorf("adding an unknown age category: err = %v, expected %v", err, json.ErrValidation)
+	}
+
+	channelNumber := "B-123"
+	update := client.Reservations.NewUpdateRequest()
+	update.ReservationUpdates = reservations.ReservationUpdates{{
+		ReservationID: added.Reservations[0].Reservation.ID,
+		ChannelNumber: &reservations.StringUpdateValue{Value: &channelNumber},
+	}}
+	_, err = client.Reservations.Update(update)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	getAll := client.Reservations.NewGetAll20230606Request()
+	getAll.CollidingUTC = configuration.TimeInterval{
+		StartUTC: start.Add(24 * time.Hour),
+		EndUTC:   start.Add(30 * 24 * time.Hour),
+	}
+	items, err := json.CollectAll(client.Reservations.GetAll20230606Iter(getAll))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].ChannelNumber != channelNumber || items[0].AccountID != customer.ID {
+		t.Errorf("got %+v", items)
+	}
+}
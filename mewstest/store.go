@@ -0,0 +1,113 @@
+package mewstest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/omniboost/go-mews/accountingcategories"
+	"github.com/omniboost/go-mews/agecategories"
+	"github.com/omniboost/go-mews/bills"
+	"github.com/omniboost/go-mews/commands"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/ledgerentries"
+	"github.com/omniboost/go-mews/orderitems"
+	"github.com/omniboost/go-mews/payments"
+	"github.com/omniboost/go-mews/rates"
+	"github.com/omniboost/go-mews/reservations"
+	"github.com/omniboost/go-mews/resources"
+	"github.com/omniboost/go-mews/services"
+)
+
+// Store is the in-memory data of a fake Server. Items are returned in the
+// order they are stored in.
+//
+// The fields can be set freely before the first request. Once the server is
+// handling requests, hold the lock while changing them.
+type Store struct {
+	sync.Mutex
+
+	// Now returns the current time of the enterprise, time.Now when nil
+	Now func() time.Time
+
+	Configuration configuration.GetResponse
+	// Enterprises of the portfolio, the enterprise of Configuration when
+	// empty
+	Enterprises                 []configuration.Enterprise
+	AccountingCategories        []accountingcategories.AccountingCategory
+	Services                    []services.Service
+	ResourceCategories          []resources.ResourceCategory
+	Resources                   []resources.Resource
+	ResourceCategoryAssignments []resources.ResourceCategoryAssignment
+	Rates                       []rates.Rate
+	AgeCategories               []agecategories.AgeCategory
+	Customers                   []customers.Customer
+	Reservations                []reservations.Reservation
+	Bills                       []bills.Bill
+	OrderItems                  []orderitems.OrderItem
+	Payments                    []payments.Payment
+	LedgerEntries               []ledgerentries.LedgerEntry
+	Commands                    []commands.Command
+
+	ids int
+}
+
+// NewStore returns an empty store for a single enterprise.
+func NewStore() *Store {
+	s := &Store{}
+	s.Configuration.Enterprise = configuration.Enterprise{
+		ID:   s.NewID(),
+		Name: "Fake Hotel",
+	}
+	return s
+}
+
+func (s *Store) now() time.Time {
+	if s.Now != nil {
+		return s.Now().UTC()
+	}
+	return time.Now().UTC()
+}
+
+// NewID returns a new unique identifier in the format of the API.
+func (s *Store) NewID() string {
+	s.ids++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.ids)
+}
+
+func (s *Store) ageCategory(id string) (int, bool) {
+	for i, c := range s.AgeCategories {
+		if c.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Store) customer(id string) (int, bool) {
+	for i, c := range s.Customers {
+		if c.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Store) reservation(id string) (int, bool) {
+	for i, r := range s.Reservations {
+		if r.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (s *Store) command(id string) (int, bool) {
+	for i, c := range s.Commands {
+		if c.ID == id {
+			return i, true
+		}
+	}
+	return 0, false
+}
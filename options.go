@@ -0,0 +1,118 @@
+package mews
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/omniboost/go-mews/json"
+)
+
+// Option configures a Client created with New.
+type Option func(*Client)
+
+// New returns a new MEWS API client for the production environment with the
+// defaults of NewClient and the client name of this library, changed by opts:
+//
+//	client := mews.New(
+//		mews.WithEnvironment(mews.Demo),
+//		mews.WithTokens(accessToken, clientToken),
+//		mews.WithClientName("My Integration 1.0.0"),
+//	)
+func New(opts ...Option) *Client {
+	c := NewClient(nil, "", "")
+	c.SetClientName(clientName)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// WithEnvironment points the REST and websocket endpoints at env.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.SetEnvironment(env)
+	}
+}
+
+// WithTokens sets the access and client token of every request and websocket
+// connection.
+func WithTokens(accessToken string, clientToken string) Option {
+	return func(c *Client) {
+		c.client.AccessToken = accessToken
+		c.client.ClientToken = clientToken
+	}
+}
+
+// WithTokenProvider resolves the tokens of every request and websocket
+// connection with provider, e.g. json.EnvTokens{} or json.NewFileTokens.
+func WithTokenProvider(provider json.TokenProvider) Option {
+	return func(c *Client) {
+		c.SetTokenProvider(provider)
+	}
+}
+
+// WithPortfolio marks the access token as a portfolio access token, see
+// Client.SetPortfolio.
+func WithPortfolio() Option {
+	return func(c *Client) {
+		c.SetPortfolio(true)
+	}
+}
+
+// WithCache serves the endpoints cached by cache from its store, e.g.
+// json.NewCache(json.NewMemoryCacheStore(), json.DefaultCacheTTLs).
+func WithCache(cache *json.Cache) Option {
+	return func(c *Client) {
+		c.Use(cache.Middleware)
+	}
+}
+
+// WithHTTPClient sends the requests with httpClient instead of
+// http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		if httpClient == nil {
+			httpClient = http.DefaultClient
+		}
+		c.client.Client = httpClient
+	}
+}
+
+// WithTimeout limits every attempt of a call to timeout, 0 disables the
+// limit.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.SetTimeout(timeout)
+	}
+}
+
+// WithRetryPolicy replaces the default retry policy. Passing nil disables
+// retries.
+func WithRetryPolicy(policy json.RetryPolicy) Option {
+	return func(c *Client) {
+		c.SetRetryPolicy(policy)
+	}
+}
+
+// WithLimiter replaces the default rate limiter. Passing nil disables it.
+func WithLimiter(limiter json.Limiter) Option {
+	return func(c *Client) {
+		c.SetLimiter(limiter)
+	}
+}
+
+// WithLogger logs every request with logger, see Client.SetLogger.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.SetLogger(logger)
+	}
+}
+
+// WithClientName sets the Client of every request, the name and version of
+// the integration as registered with Mews.
+func WithClientName(name string) Option {
+	return func(c *Client) {
+		c.SetClientName(name)
+	}
+}
@@ -0,0 +1,106 @@
+package mews_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	mews "github.com/omniboost/go-mews"
+	base "github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+)
+
+func TestNewEnvironment(t *testing.T) {
+	tests := []struct {
+		name     string
+		client   *mews.Client
+		expected string
+	}{
+		{"default", mews.New(), mews.WebsocketURL.Host},
+		{"demo", mews.New(mews.WithEnvironment(mews.Demo)), mews.WebsocketURLDemo.Host},
+		{"custom", mews.New(mews.WithEnvironment(mews.Custom(mews.BaseURL, mews.WebsocketURLDemo))), mews.WebsocketURLDemo.Host},
+	}
+
+	for _, test := range tests {
+		ws := test.client.GetWebsocket(context.Background())
+		if host := ws.BaseURL().Host; host != test.expected {
+			t.Errorf("%s: websocket host is %s, expected %s", test.name, host, test.expected)
+		}
+	}
+
+	// switching the base URL of a client to demo switches the websocket too
+	client := mews.NewClient(nil, "access", "client")
+	client.SetBaseURL(mews.BaseURLDemo)
+	if host := client.GetWebsocket(context.Background()).BaseURL().Host; host != mews.WebsocketURLDemo.Host {
+		t.Errorf("websocket host is %s, expected %s", host, mews.WebsocketURLDemo.Host)
+	}
+}
+
+func TestCustomEnvironmentWithoutWebsocket(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+
+	clients := map[string]*mews.Client{
+		"custom":   mews.New(mews.WithEnvironment(mews.Custom(server.BaseURL(), nil))),
+		"base URL": mews.NewClient(nil, "access", "client"),
+	}
+	clients["base URL"].SetBaseURL(server.BaseURL())
+
+	for name, client := range clients {
+		ws := client.GetWebsocket(context.Background())
+		if ws.BaseURL() != nil {
+			t.Errorf("%s: websocket connects to %s, expected no endpoint", name, ws.BaseURL())
+		}
+		if err := ws.Connect(context.Background()); !errors.Is(err, mews.ErrNoWebsocketURL) {
+			t.Errorf("%s: unexpected error %v", name, err)
+		}
+	}
+}
+
+func TestWebsocketTokenError(t *testing.T) {
+	// the tokens are resolved before dialing, the demo endpoint isn't reached
+	client := mews.New(mews.WithEnvironment(mews.Demo), mews.WithTokenProvider(poolTokens{}))
+	ctx := base.WithOptions(context.Background(), base.WithEnterpriseID("e1"))
+
+	err := client.GetWebsocket(ctx).Connect(ctx)
+	var terr *base.TokenError
+	if !errors.As(err, &terr) || !errors.Is(err, base.ErrUnknownEnterprise) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if terr.EnterpriseID != "e1" {
+		t.Errorf("token error is for enterprise %q, expected e1", terr.EnterpriseID)
+	}
+}
+
+func TestNewClientName(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+
+	var name string
+	server.Handle("configuration/get", func(body []byte) (interface{}, error) {
+		req := struct{ Client string }{}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return nil, err
+		}
+		name = req.Client
+		return server.Store.Configuration, nil
+	})
+
+	client := mews.New(
+		mews.WithEnvironment(mews.Custom(server.BaseURL(), nil)),
+		mews.WithHTTPClient(server.Client()),
+		mews.WithTokens(server.AccessToken, server.ClientToken),
+		mews.WithRetryPolicy(nil),
+		mews.WithLimiter(nil),
+		mews.WithClientName("Test Integration 1.0.0"),
+	)
+
+	_, err := client.Configuration.Get(client.Configuration.NewGetRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "Test Integration 1.0.0" {
+		t.Errorf("request was sent with client %q", name)
+	}
+}
@@ -1,6 +1,8 @@
 package orderitems
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/cydev/zero"
@@ -15,31 +17,108 @@ const (
 
 // List all orderitems
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
+
+// AllContext is All with a context and per-call options. More than
+// base.MaxIDs BillIDs are split into chunks and ConsumedUTC and ClosedUTC
+// intervals longer than base.MaxIntervalMonths into windows. The pages of every
+// chunk and window are fetched and merged into one response without a cursor.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	if len(requestBody.BillIDs) > base.MaxIDs {
+		return base.FetchChunks(ctx, requestBody.BillIDs, base.MaxIDs, func(ctx context.Context, ids []string) (*AllResponse, error) {
+			chunk := *requestBody
+			chunk.BillIDs = ids
+			chunk.Limitation.Cursor = ""
+			chunk.SetContext(ctx)
+			return base.MergePages(&chunk, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
+	}
+
+	if windows := base.Windows(requestBody.ConsumedUTC.StartUTC, requestBody.ConsumedUTC.EndUTC, base.MaxIntervalMonths); len(windows) > 1 {
+		return base.FetchWindows(ctx, windows, func(ctx context.Context, w base.Window) (*AllResponse, error) {
+			window := *requestBody
+			window.ConsumedUTC = configuration.TimeInterval{StartUTC: w.Start, EndUTC: w.End}
+			window.Limitation.Cursor = ""
+			window.SetContext(ctx)
+			return base.MergePages(&window, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
+	}
+
+	if windows := base.Windows(requestBody.ClosedUTC.StartUTC, requestBody.ClosedUTC.EndUTC, base.MaxIntervalMonths); len(windows) > 1 {
+		return base.FetchWindows(ctx, windows, func(ctx context.Context, w base.Window) (*AllResponse, error) {
+			window := *requestBody
+			window.ClosedUTC = configuration.TimeInterval{StartUTC: w.Start, EndUTC: w.End}
+			window.Limitation.Cursor = ""
+			window.SetContext(ctx)
+			return base.MergePages(&window, func(r *AllRequest) (*AllResponse, error) {
+				return s.AllContext(ctx, r, opts...)
+			})
+		}, opts...)
+	}
+
 	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
+	apiURL, err := s.Client.GetApiURL(endpointAll)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody := &AllResponse{}
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
+	if err != nil {
 		return nil, err
 	}
 
+	_, err = s.Client.Do(httpReq, responseBody)
+	return responseBody, err
+}
+
+// AllStream is AllContext for pages too large to keep in memory: the order
+// items are passed to item as they are decoded instead of being kept in the
+// response, which only has the Cursor.
+func (s *Service) AllStream(ctx context.Context, requestBody *AllRequest, item func(OrderItem) error, opts ...base.RequestOption) (*AllResponse, error) {
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
 
-	_, err = s.Client.Do(httpReq, responseBody)
+	_, err = s.Client.Do(httpReq, base.NewStream("OrderItems", responseBody, item))
 	return responseBody, err
 }
 
+// AllStreamIter is AllIter with AllStream: the order items of every page are
+// decoded one at a time.
+func (s *Service) AllStreamIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[OrderItem, error] {
+	return base.StreamItems(requestBody, func(r *AllRequest, item func(OrderItem) error) (*AllResponse, error) {
+		return s.AllStream(r.GetContext(), r, item)
+	}, opts...)
+}
+
+// AllIter iterates over all order items, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[OrderItem, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []OrderItem { return r.OrderItems }, opts...)
+}
+
 type AllResponse struct {
 	OrderItems OrderItems
 	Cursor     string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -67,6 +146,24 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks the lengths of the ID lists and intervals.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.Interval("CreatedUtc", r.CreatedUTC.StartUTC, r.CreatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ConsumedUtc", r.ConsumedUTC.StartUTC, r.ConsumedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("CanceledUtc", r.CanceledUTC.StartUTC, r.CanceledUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ClosedUtc", r.ClosedUTC.StartUTC, r.ClosedUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("OrderItemIds", len(r.OrderItemIDs), base.MaxIDs)
+	v.MaxItems("ServiceOrderIds", len(r.ServiceOrderIDs), base.MaxIDs)
+	v.MaxItems("BillIds", len(r.BillIDs), base.MaxIDs)
+	return v.Err()
+}
+
 type OrderItems []OrderItem
 
 type OrderItem struct {
@@ -1,6 +1,9 @@
 package outlets
 
 import (
+	"context"
+	"iter"
+
 	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
 )
@@ -11,18 +14,19 @@ const (
 
 // List all outlets
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -31,11 +35,21 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all outlets, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Outlet, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Outlet { return r.Outlets }, opts...)
+}
+
 type AllResponse struct {
 	Outlets Outlets
 	Cursor  string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *APIService) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -49,6 +63,10 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type Outlets []Outlet
 
 type Outlet struct {
@@ -1,6 +1,8 @@
 package payments
 
 import (
+	"context"
+
 	"github.com/omniboost/go-mews/accountingitems"
 	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -14,18 +16,19 @@ const (
 // payment represents a payment that is tracked outside of the system. Note this
 // operation supports Portfolio Access Tokens.
 func (s *Service) AddExternal(requestBody *AddExternalRequest) (*AddExternalResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AddExternalContext(requestBody.GetContext(), requestBody)
+}
 
+// AddExternalContext is AddExternal with a context and per-call options.
+func (s *Service) AddExternalContext(ctx context.Context, requestBody *AddExternalRequest, opts ...base.RequestOption) (*AddExternalResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAddExternal)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AddExternalResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
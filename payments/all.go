@@ -1,6 +1,8 @@
 package payments
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all Payments
 func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *Service) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +37,21 @@ func (s *Service) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all payments, following the response cursor until
+// every page has been fetched.
+func (s *Service) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Payment, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Payment { return r.Payments }, opts...)
+}
+
 type AllResponse struct {
 	Payments Payments
 	Cursor   string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *Service) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -64,6 +77,23 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks the lengths of the ID lists and intervals.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.Interval("CreatedUtc", r.CreatedUTC.StartUTC, r.CreatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ChargedUtc", r.ChargedUTC.StartUTC, r.ChargedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("ClosedUtc", r.ClosedUTC.StartUTC, r.ClosedUTC.EndUTC, base.MaxIntervalMonths)
+	v.Interval("SettlementUtc", r.SettlementUTC.StartUTC, r.SettlementUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("PaymentIds", len(r.PaymentIDs), base.MaxIDs)
+	v.MaxItems("BillIds", len(r.BillIDs), base.MaxIDs)
+	return v.Err()
+}
+
 type Payments []Payment
 
 type Payment struct {
@@ -0,0 +1,254 @@
+package mews
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrQueryNotRun = errors.New("Query has not run")
+)
+
+// Plan is a set of queries that are run concurrently, each one after the
+// queries it depends on. The queries share the rate limit of the clients they
+// use, so a Plan replaces hand-written errgroup code:
+//
+//	plan := mews.NewPlan()
+//	svcs := mews.AddQuery(plan, "services", func(ctx context.Context, r *mews.Results) (*services.AllResponse, error) {
+//		return client.Services.AllContext(ctx, client.Services.NewAllRequest())
+//	})
+//	rsvs := mews.AddQuery(plan, "reservations", func(ctx context.Context, r *mews.Results) (*reservations.AllResponse, error) {
+//		resp, _ := svcs.Get(r) // done, without error
+//		req := client.Reservations.NewAllRequest()
+//		req.ServiceIDs = serviceIDs(resp)
+//		return client.Reservations.AllContext(ctx, req)
+//	}, svcs)
+//
+//	results := plan.Run(ctx)
+//	resp, err := rsvs.Get(results)
+type Plan struct {
+	concurrency int
+	queries     []*planQuery
+	names       map[string]bool
+}
+
+// PlanOption configures a Plan created with NewPlan.
+type PlanOption func(*Plan)
+
+func NewPlan(opts ...PlanOption) *Plan {
+	p := &Plan{names: map[string]bool{}}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithPlanConcurrency runs at most n queries of a plan at the same time, 0 is
+// unlimited.
+func WithPlanConcurrency(n int) PlanOption {
+	return func(p *Plan) {
+		p.concurrency = n
+	}
+}
+
+type planQuery struct {
+	name  string
+	after []string
+	run   func(ctx context.Context, r *Results) (interface{}, error)
+}
+
+// Dependency is a query another query waits for, see AddQuery.
+type Dependency interface {
+	Name() string
+}
+
+// Query is a query added to a Plan. Its result is read from the Results of
+// the plan with Get.
+type Query[T any] struct {
+	name string
+}
+
+// AddQuery adds a query named name to p that runs fetch after the queries in
+// after finished without an error. fetch reads their results from r. AddQuery
+// panics when the plan already has a query named name or doesn't have one of
+// the dependencies, so a plan can't have cycles.
+func AddQuery[T any](p *Plan, name string, fetch func(ctx context.Context, r *Results) (T, error), after ...Dependency) Query[T] {
+	if p.names[name] {
+		panic(fmt.Sprintf("mews: plan already has a query %q", name))
+	}
+
+	q := &planQuery{
+		name: name,
+		run: func(ctx context.Context, r *Results) (interface{}, error) {
+			return fetch(ctx, r)
+		},
+	}
+	for _, dep := range after {
+		if !p.names[dep.Name()] {
+			panic(fmt.Sprintf("mews: query %q depends on unknown query %q", name, dep.Name()))
+		}
+		q.after = append(q.after, dep.Name())
+	}
+
+	p.names[name] = true
+	p.queries = append(p.queries, q)
+	return Query[T]{name: name}
+}
+
+func (q Query[T]) Name() string {
+	return q.name
+}
+
+// Get returns the result and error of the query in r. The error is
+// ErrQueryNotRun when the query hasn't finished, e.g. when it is read by a
+// query that doesn't depend on it.
+func (q Query[T]) Get(r *Results) (T, error) {
+	v, err := r.get(q.name)
+	t, _ := v.(T)
+	return t, err
+}
+
+// DependencyError is the error of a query that didn't run because a query it
+// depends on failed.
+type DependencyError struct {
+	Query      string
+	Dependency string
+	Err        error
+}
+
+func (e *DependencyError) Error() string {
+	return fmt.Sprintf("%s: dependency %s failed: %s", e.Query, e.Dependency, e.Err)
+}
+
+func (e *DependencyError) Unwrap() error {
+	return e.Err
+}
+
+// Results are the results of the queries of a plan. They are safe for
+// concurrent use.
+type Results struct {
+	order []string
+
+	mu        sync.Mutex
+	values    map[string]interface{}
+	errs      map[string]error
+	durations map[string]time.Duration
+}
+
+func (r *Results) get(name string) (interface{}, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	v, ok := r.values[name]
+	if !ok {
+		return nil, ErrQueryNotRun
+	}
+	return v, r.errs[name]
+}
+
+func (r *Results) set(name string, v interface{}, err error, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.values[name] = v
+	if err != nil {
+		r.errs[name] = err
+	}
+	r.durations[name] = d
+}
+
+// Errs returns the errors of the queries that failed or didn't run, by name.
+func (r *Results) Errs() map[string]error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errs := make(map[string]error, len(r.errs))
+	for name, err := range r.errs {
+		errs[name] = err
+	}
+	return errs
+}
+
+// Err returns the errors of all queries joined in the order they were added,
+// or nil when every query succeeded.
+func (r *Results) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	errs := []error{}
+	for _, name := range r.order {
+		if err := r.errs[name]; err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Duration returns how long the query took, 0 when it didn't run.
+func (r *Results) Duration(name string) time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.durations[name]
+}
+
+// Run runs the queries of the plan and returns their results once all of them
+// finished. A query whose dependency failed isn't run and gets a
+// *DependencyError. When ctx is done, the queries that didn't start get its
+// error.
+func (p *Plan) Run(ctx context.Context) *Results {
+	r := &Results{
+		values:    map[string]interface{}{},
+		errs:      map[string]error{},
+		durations: map[string]time.Duration{},
+	}
+
+	done := make(map[string]chan struct{}, len(p.queries))
+	for _, q := range p.queries {
+		r.order = append(r.order, q.name)
+		done[q.name] = make(chan struct{})
+	}
+
+	var sem chan struct{}
+	if p.concurrency > 0 {
+		sem = make(chan struct{}, p.concurrency)
+	}
+
+	var wg sync.WaitGroup
+	for _, q := range p.queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(done[q.name])
+
+			for _, dep := range q.after {
+				<-done[dep]
+				if _, err := r.get(dep); err != nil {
+					r.set(q.name, nil, &DependencyError{Query: q.name, Dependency: dep, Err: err}, 0)
+					return
+				}
+			}
+
+			if sem != nil {
+				select {
+				case sem <- struct{}{}:
+					defer func() { <-sem }()
+				case <-ctx.Done():
+				}
+			}
+			if err := ctx.Err(); err != nil {
+				r.set(q.name, nil, err, 0)
+				return
+			}
+
+			start := time.Now()
+			v, err := q.run(ctx, r)
+			r.set(q.name, v, err, time.Since(start))
+		}()
+	}
+	wg.Wait()
+
+	return r
+}
@@ -0,0 +1,134 @@
+package mews_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	mews "github.com/omniboost/go-mews"
+	"github.com/omniboost/go-mews/bills"
+	"github.com/omniboost/go-mews/configuration"
+	"github.com/omniboost/go-mews/customers"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+	"github.com/omniboost/go-mews/orderitems"
+)
+
+func TestPlanRun(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+	server.Store.Configuration.Enterprise.ID = "enterprise"
+	server.Store.Customers = []customers.Customer{{ID: "c1"}, {ID: "c2"}}
+	server.Fail("bills/getAll", mewstest.Fault{Status: http.StatusBadRequest})
+	client := server.NewClient()
+
+	plan := mews.NewPlan()
+	config := mews.AddQuery(plan, "configuration", func(ctx context.Context, r *mews.Results) (*configuration.GetResponse, error) {
+		return client.Configuration.GetContext(ctx, client.Configuration.NewGetRequest())
+	})
+	custs := mews.AddQuery(plan, "customers", func(ctx context.Context, r *mews.Results) (*customers.AllResponse, error) {
+		resp, err := config.Get(r)
+		if err != nil || resp.Enterprise.ID != "enterprise" {
+			t.Errorf("customers ran before configuration: %v", err)
+		}
+		req := client.Customers.NewAllRequest()
+		req.CustomerIDs = []string{"c1", "c2"}
+		req.Limitation.Count = 10
+		return client.Customers.AllContext(ctx, req)
+	}, config)
+	bls := mews.AddQuery(plan, "bills", func(ctx context.Context, r *mews.Results) (*bills.AllResponse, error) {
+		req := client.Bills.NewAllRequest()
+		req.BillIDs = []string{"b1"}
+		req.Limitation.Count = 10
+		return client.Bills.AllContext(ctx, req)
+	})
+	items := mews.AddQuery(plan, "orderitems", func(ctx context.Context, r *mews.Results) (*orderitems.AllResponse, error) {
+		t.Error("orderitems ran although bills failed")
+		return nil, nil
+	}, bls)
+
+	results := plan.Run(context.Background())
+
+	resp, err := custs.Get(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Customers) != 2 {
+		t.Errorf("len(Customers) = %d, expected 2", len(resp.Customers))
+	}
+
+	if _, err := bls.Get(results); !errors.Is(err, json.ErrValidation) {
+		t.Errorf("bills: unexpected error %v", err)
+	}
+	_, err = items.Get(results)
+	var derr *mews.DependencyError
+	if !errors.As(err, &derr) || derr.Dependency != "bills" {
+		t.Errorf("orderitems: unexpected error %v", err)
+	}
+
+	if errs := results.Errs(); len(errs) != 2 {
+		t.Errorf("errors of %d queries, expected 2", len(errs))
+	}
+	if results.Err() == nil {
+		t.Error("expected the joined errors")
+	}
+}
+
+func TestPlanConcurrency(t *testing.T) {
+	plan := mews.NewPlan(mews.WithPlanConcurrency(2))
+
+	var running, maxRunning atomic.Int32
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		mews.AddQuery(plan, name, func(ctx context.Context, r *mews.Results) (bool, error) {
+			n := running.Add(1)
+			defer running.Add(-1)
+			for {
+				m := maxRunning.Load()
+				if n <= m || maxRunning.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return true, nil
+		})
+	}
+
+	results := plan.Run(context.Background())
+	if err := results.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if n := maxRunning.Load(); n != 2 {
+		t.Errorf("%d queries ran at the same time, expected 2", n)
+	}
+}
+
+func TestPlanCanceled(t *testing.T) {
+	plan := mews.NewPlan()
+	q := mews.AddQuery(plan, "q", func(ctx context.Context, r *mews.Results) (int, error) {
+		return 1, nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := q.Get(plan.Run(ctx)); !errors.Is(err, context.Canceled) {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestAddQueryUnknownDependency(t *testing.T) {
+	other := mews.AddQuery(mews.NewPlan(), "other", func(ctx context.Context, r *mews.Results) (int, error) {
+		return 1, nil
+	})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic")
+		}
+	}()
+	mews.AddQuery(mews.NewPlan(), "q", func(ctx context.Context, r *mews.Results) (int, error) {
+		return 1, nil
+	}, other)
+}
@@ -0,0 +1,300 @@
+package mews
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/omniboost/go-mews/json"
+)
+
+// Pool hands out a Client per enterprise for processes that sync many
+// enterprises. The clients are created on first use with the tokens of the
+// enterprise and share one http.Transport, one rate limiter with a bucket per
+// access token and a limit on the number of requests in flight. A Pool is safe
+// for concurrent use.
+type Pool struct {
+	tokens    json.TokenProvider
+	options   []Option
+	transport http.RoundTripper
+	limiter   json.Limiter
+	// semaphore of the requests in flight, nil when unlimited
+	inFlight chan struct{}
+
+	mu      sync.Mutex
+	tenants map[string]*tenant
+}
+
+// PoolOption configures a Pool created with NewPool.
+type PoolOption func(*Pool)
+
+// NewPool returns a pool that resolves the tokens of an enterprise with
+// tokens, e.g. json.NewFileTokens. The provider is called with the enterprise
+// in the RequestOptions of the context.
+func NewPool(tokens json.TokenProvider, opts ...PoolOption) *Pool {
+	p := &Pool{
+		tokens:    tokens,
+		transport: http.DefaultTransport.(*http.Transport).Clone(),
+		limiter:   json.NewRateLimiter(json.DefaultRateLimit),
+		tenants:   map[string]*tenant{},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// WithPoolTransport sends the requests of all clients with transport.
+func WithPoolTransport(transport http.RoundTripper) PoolOption {
+	return func(p *Pool) {
+		p.transport = transport
+	}
+}
+
+// WithPoolLimiter replaces the rate limiter shared by all clients. Passing nil
+// disables it.
+func WithPoolLimiter(limiter json.Limiter) PoolOption {
+	return func(p *Pool) {
+		p.limiter = limiter
+	}
+}
+
+// WithMaxConcurrency limits the requests in flight of all clients together to
+// n, 0 is unlimited.
+func WithMaxConcurrency(n int) PoolOption {
+	return func(p *Pool) {
+		p.inFlight = nil
+		if n > 0 {
+			p.inFlight = make(chan struct{}, n)
+		}
+	}
+}
+
+// WithClientOptions applies opts to every client the pool creates, after the
+// settings of the pool.
+func WithClientOptions(opts ...Option) PoolOption {
+	return func(p *Pool) {
+		p.options = append(p.options, opts...)
+	}
+}
+
+// Client returns the client of enterpriseID, creating it the first time. The
+// tokens of the enterprise are resolved to check them, a failure is returned
+// as a *json.TokenError and the client isn't kept.
+func (p *Pool) Client(ctx context.Context, enterpriseID string) (*Client, error) {
+	p.mu.Lock()
+	t, ok := p.tenants[enterpriseID]
+	p.mu.Unlock()
+	if ok {
+		return t.client, nil
+	}
+
+	// resolving the tokens can take a while, don't block the other calls
+	tokens := &enterpriseTokens{provider: p.tokens, enterpriseID: enterpriseID}
+	if _, err := json.ResolveTokens(json.WithOptions(ctx, json.WithEnterpriseID(enterpriseID)), p.tokens); err != nil {
+		return nil, err
+	}
+
+	t = &tenant{}
+	opts := []Option{
+		WithHTTPClient(&http.Client{Transport: &limitedTransport{transport: p.transport, inFlight: p.inFlight}}),
+		WithTokenProvider(tokens),
+		WithLimiter(nil),
+	}
+	if p.limiter != nil {
+		opts = append(opts, WithLimiter(&tenantLimiter{Limiter: p.limiter, tenant: t}))
+	}
+	t.client = New(append(opts, p.options...)...)
+	t.client.Use(t.record)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	// another call may have created the client in the meantime
+	if existing, ok := p.tenants[enterpriseID]; ok {
+		return existing.client, nil
+	}
+	p.tenants[enterpriseID] = t
+	return t.client, nil
+}
+
+// Enterprises returns the enterprises the pool has a client for.
+func (p *Pool) Enterprises() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]string, 0, len(p.tenants))
+	for id := range p.tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Health returns the health of the client of enterpriseID, false when the
+// pool has no client for it.
+func (p *Pool) Health(enterpriseID string) (TenantHealth, bool) {
+	p.mu.Lock()
+	t, ok := p.tenants[enterpriseID]
+	p.mu.Unlock()
+
+	if !ok {
+		return TenantHealth{}, false
+	}
+	return t.health(), true
+}
+
+// Remove drops the client of enterpriseID, e.g. after its tokens were
+// revoked. The next call to Client creates a new one.
+func (p *Pool) Remove(enterpriseID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.tenants, enterpriseID)
+}
+
+// CloseIdleConnections closes the idle connections of the shared transport.
+func (p *Pool) CloseIdleConnections() {
+	if t, ok := p.transport.(interface{ CloseIdleConnections() }); ok {
+		t.CloseIdleConnections()
+	}
+}
+
+// TenantHealth is a snapshot of the calls made by the client of an
+// enterprise.
+type TenantHealth struct {
+	Calls    int
+	Failures int
+	// Last failed call and its error
+	LastError   error
+	LastErrorAt time.Time
+	LastSuccess time.Time
+	// Number of "429 - Too many requests" responses
+	Throttled int
+	// Time spent waiting for the rate limiter
+	ThrottledFor time.Duration
+	// Set while the API throttles the access token of the enterprise
+	ThrottledUntil time.Time
+}
+
+type tenant struct {
+	client *Client
+
+	mu sync.Mutex
+	h  TenantHealth
+}
+
+func (t *tenant) health() TenantHealth {
+	t.mu.Lock()
+	h := t.h
+	t.mu.Unlock()
+
+	if state, ok := t.client.LimiterState(); ok && state.ThrottledUntil.After(time.Now()) {
+		h.ThrottledUntil = state.ThrottledUntil
+	}
+	return h
+}
+
+// record is the middleware that keeps the health of the tenant.
+func (t *tenant) record(next json.Handler) json.Handler {
+	return func(call *json.Call) (*http.Response, error) {
+		resp, err := next(call)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.h.Calls++
+		if err != nil {
+			t.h.Failures++
+			t.h.LastError = err
+			t.h.LastErrorAt = time.Now()
+		} else {
+			t.h.LastSuccess = time.Now()
+		}
+		return resp, err
+	}
+}
+
+// tenantLimiter measures the throttling of a tenant on the shared limiter.
+type tenantLimiter struct {
+	json.Limiter
+	tenant *tenant
+}
+
+func (l *tenantLimiter) Wait(ctx context.Context, token string, endpoint string) error {
+	start := time.Now()
+	err := l.Limiter.Wait(ctx, token, endpoint)
+
+	l.tenant.mu.Lock()
+	l.tenant.h.ThrottledFor += time.Since(start)
+	l.tenant.mu.Unlock()
+	return err
+}
+
+func (l *tenantLimiter) Throttle(token string, until time.Time) {
+	l.tenant.mu.Lock()
+	l.tenant.h.Throttled++
+	l.tenant.mu.Unlock()
+
+	l.Limiter.Throttle(token, until)
+}
+
+func (l *tenantLimiter) State(token string) json.LimiterState {
+	s, ok := l.Limiter.(interface {
+		State(token string) json.LimiterState
+	})
+	if !ok {
+		return json.LimiterState{}
+	}
+	return s.State(token)
+}
+
+// enterpriseTokens resolves the tokens of one enterprise.
+type enterpriseTokens struct {
+	provider     json.TokenProvider
+	enterpriseID string
+}
+
+func (t *enterpriseTokens) Tokens(ctx context.Context) (json.Tokens, error) {
+	ctx = json.WithOptions(ctx, json.WithEnterpriseID(t.enterpriseID))
+	return t.provider.Tokens(ctx)
+}
+
+// limitedTransport limits the number of requests in flight.
+type limitedTransport struct {
+	transport http.RoundTripper
+	inFlight  chan struct{}
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.inFlight == nil {
+		return t.transport.RoundTrip(req)
+	}
+
+	select {
+	case t.inFlight <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+
+	resp, err := t.transport.RoundTrip(req)
+	if err != nil {
+		<-t.inFlight
+		return nil, err
+	}
+	// the request is in flight until its body is read
+	resp.Body = &releaseBody{ReadCloser: resp.Body, release: func() { <-t.inFlight }}
+	return resp, nil
+}
+
+type releaseBody struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (b *releaseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
@@ -0,0 +1,185 @@
+package mews_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	mews "github.com/omniboost/go-mews"
+	"github.com/omniboost/go-mews/json"
+	"github.com/omniboost/go-mews/mewstest"
+)
+
+type poolTokens map[string]json.Tokens
+
+func (p poolTokens) Tokens(ctx context.Context) (json.Tokens, error) {
+	tokens, ok := p[json.OptionsFromContext(ctx).EnterpriseID]
+	if !ok {
+		return json.Tokens{}, json.ErrUnknownEnterprise
+	}
+	return tokens, nil
+}
+
+func newTestPool(server *mewstest.Server, opts ...mews.PoolOption) *mews.Pool {
+	tokens := json.Tokens{AccessToken: server.AccessToken, ClientToken: server.ClientToken}
+	opts = append([]mews.PoolOption{
+		mews.WithPoolTransport(server.Client().Transport),
+		mews.WithClientOptions(
+			mews.WithEnvironment(mews.Custom(server.BaseURL(), nil)),
+			mews.WithRetryPolicy(nil),
+		),
+	}, opts...)
+	return mews.NewPool(poolTokens{"e1": tokens, "e2": tokens}, opts...)
+}
+
+func TestPoolClient(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+	pool := newTestPool(server)
+
+	client, err := pool.Client(context.Background(), "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := pool.Client(context.Background(), "e1")
+	if client != again {
+		t.Error("expected the same client for the same enterprise")
+	}
+
+	_, err = pool.Client(context.Background(), "e3")
+	var terr *json.TokenError
+	if !errors.As(err, &terr) || !errors.Is(err, json.ErrUnknownEnterprise) {
+		t.Errorf("unexpected error %v", err)
+	}
+	if ids := pool.Enterprises(); len(ids) != 1 || ids[0] != "e1" {
+		t.Errorf("pool has clients for %v", ids)
+	}
+}
+
+// slowTokens blocks resolving the tokens of e2 until release is closed.
+type slowTokens struct {
+	poolTokens
+	release chan struct{}
+}
+
+func (p slowTokens) Tokens(ctx context.Context) (json.Tokens, error) {
+	if json.OptionsFromContext(ctx).EnterpriseID == "e2" {
+		<-p.release
+	}
+	return p.poolTokens.Tokens(ctx)
+}
+
+func TestPoolClientResolvesTokensUnlocked(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+	tokens := json.Tokens{AccessToken: server.AccessToken, ClientToken: server.ClientToken}
+	provider := slowTokens{poolTokens{"e1": tokens, "e2": tokens}, make(chan struct{})}
+	pool := mews.NewPool(provider, mews.WithPoolTransport(server.Client().Transport))
+
+	client, err := pool.Client(context.Background(), "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	clients := make([]*mews.Client, 2)
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			clients[i], _ = pool.Client(context.Background(), "e2")
+		}()
+	}
+
+	// the client of e1 is returned while the tokens of e2 are resolved
+	again, _ := pool.Client(context.Background(), "e1")
+	if again != client {
+		t.Error("expected the same client for the same enterprise")
+	}
+	close(provider.release)
+	wg.Wait()
+
+	if clients[0] == nil || clients[0] != clients[1] {
+		t.Error("expected one client for e2")
+	}
+}
+
+func TestPoolHealth(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+	pool := newTestPool(server)
+
+	client, err := pool.Client(context.Background(), "e1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Configuration.Get(client.Configuration.NewGetRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	server.Fail("configuration/get", mewstest.Fault{Status: http.StatusTooManyRequests})
+	_, err = client.Configuration.Get(client.Configuration.NewGetRequest())
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	h, ok := pool.Health("e1")
+	if !ok {
+		t.Fatal("no health for e1")
+	}
+	if h.Calls != 2 || h.Failures != 1 || h.Throttled != 1 || h.LastError == nil || h.LastSuccess.IsZero() {
+		t.Errorf("unexpected health %+v", h)
+	}
+	if h.ThrottledUntil.IsZero() {
+		t.Error("expected the enterprise to be throttled")
+	}
+
+	if _, ok := pool.Health("e2"); ok {
+		t.Error("expected no health for e2 before its client is created")
+	}
+}
+
+func TestPoolMaxConcurrency(t *testing.T) {
+	server := mewstest.NewServer()
+	defer server.Close()
+
+	inFlight, maxInFlight := &atomic.Int32{}, &atomic.Int32{}
+	server.Handle("configuration/get", func(body []byte) (interface{}, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		return server.Store.Configuration, nil
+	})
+
+	pool := newTestPool(server, mews.WithMaxConcurrency(2), mews.WithPoolLimiter(nil))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(enterpriseID string) {
+			defer wg.Done()
+			client, err := pool.Client(context.Background(), enterpriseID)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := client.Configuration.Get(client.Configuration.NewGetRequest()); err != nil {
+				t.Error(err)
+			}
+		}([]string{"e1", "e2"}[i%2])
+	}
+	wg.Wait()
+
+	if n := maxInFlight.Load(); n > 2 {
+		t.Errorf("%d requests were in flight, expected at most 2", n)
+	}
+}
@@ -1,6 +1,9 @@
 package products
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 	base "github.com/omniboost/go-mews/json"
@@ -13,18 +16,19 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -33,11 +37,21 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all products, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Product, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Product { return r.Products }, opts...)
+}
+
 type AllResponse struct {
 	Products Products
 	Cursor   string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *APIService) NewAllRequest() *AllRequest {
 	return &AllRequest{
 		IncludeDefault: true,
@@ -54,6 +68,10 @@ type AllRequest struct {
 	Limitation     json.Limitation             `json:"Limitation,omitempty"`
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type Products []Product
 
 type Product struct {
@@ -1,6 +1,8 @@
 package productserviceorders
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all productserviceorders
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +37,21 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all product service orders, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[ProductServiceOrder, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []ProductServiceOrder { return r.ProductServiceOrders }, opts...)
+}
+
 type AllResponse struct {
 	ProductServiceOrders ProductServiceOrders
 	Cursor               string `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *APIService) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -57,6 +70,10 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type ProductServiceOrders []ProductServiceOrder
 
 type ProductServiceOrder struct {
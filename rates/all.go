@@ -1,6 +1,9 @@
 package rates
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 )
@@ -11,18 +14,19 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -31,12 +35,22 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all rates, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...json.PageOption) iter.Seq2[Rate, error] {
+	return json.Items(requestBody, s.All, func(r *AllResponse) []Rate { return r.Rates }, opts...)
+}
+
 type AllResponse struct {
 	Rates      Rates      `json:"Rates"`      // Rates of the default service.
 	RateGroups RateGroups `json:"RateGroups"` // Rate groups of the default service.
 	Cursor     string     `json:"Cursor"`     // Cursor for pagination.
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type Rates []Rate
 
 type Rate struct {
@@ -88,6 +102,10 @@ type AllRequest struct {
 	Extent RateExtent `json:"Extent"`
 }
 
+func (r *AllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type RateExtent struct {
 	// Whether the response should contain rates.
 	Rates bool `json:"Rates"`
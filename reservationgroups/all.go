@@ -1,6 +1,9 @@
 package reservationgroups
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -12,18 +15,19 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +36,12 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all reservation groups, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[ReservationGroup, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []ReservationGroup { return r.ReservationGroups }, opts...)
+}
+
 func (s *APIService) NewAllRequest() *AllRequest {
 	return &AllRequest{}
 }
@@ -57,11 +67,30 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
+// Validate checks that a filter is set and the lengths of the ID list and
+// interval.
+func (r *AllRequest) Validate() error {
+	v := &base.Validation{}
+	v.AnyOf(len(r.ReservationGroupIDs) > 0 || !r.UpdatedUTC.IsEmpty(),
+		"ReservationGroupIds", "UpdatedUtc")
+	v.Interval("UpdatedUtc", r.UpdatedUTC.StartUTC, r.UpdatedUTC.EndUTC, base.MaxIntervalMonths)
+	v.MaxItems("ReservationGroupIds", len(r.ReservationGroupIDs), base.MaxIDs)
+	return v.Err()
+}
+
 type AllResponse struct {
 	ReservationGroups ReservationGroups `json:"ReservationGroups"`
 	Cursor            string            `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ReservationGroups []ReservationGroup
 
 type ReservationGroup struct {
@@ -1,6 +1,8 @@
 package reservations
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/accountingitems"
@@ -19,18 +21,19 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -39,21 +42,31 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all reservations, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[Reservation, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []Reservation { return r.Reservations }, opts...)
+}
+
 type AllResponse struct {
-	BusinessSegments            BusinessSegments                        `json:"BusinessSegments"`  //  Business segments of the reservations.
-	Customers                   customers.Customers                     `json:"Customers"`         // Customers that are members of the reservations.
-	Items                       accountingitems.AccountingItems         `json:"Items"`             // Revenue items of the reservations.
-	Products                    Products                                `json:"Products"`          // Products orderable with reservations.
-	RateGroups                  RateGroups                              `json:"RateGroups"`        // Rate groups of the reservation rates.
-	Rates                       Rates                                   `json:"Rates"`             // Rates of the reservations.
-	ReservationGroups           ReservationGroups                       `json:"ReservationGroups"` // Reservation groups that the reservations are members of.
-	Reservations                Reservations                            `json:"Reservations"`      // The reservations that collide with the specified interval.
-	Services                    Services                                `json:"Services"`          // Services that have been reserved.
+	BusinessSegments            BusinessSegments                      `json:"BusinessSegments"`  //  Business segments of the reservations.
+	Customers                   customers.Customers                   `json:"Customers"`         // Customers that are members of the reservations.
+	Items                       accountingitems.AccountingItems       `json:"Items"`             // Revenue items of the reservations.
+	Products                    Products                              `json:"Products"`          // Products orderable with reservations.
+	RateGroups                  RateGroups                            `json:"RateGroups"`        // Rate groups of the reservation rates.
+	Rates                       Rates                                 `json:"Rates"`             // Rates of the reservations.
+	ReservationGroups           ReservationGroups                     `json:"ReservationGroups"` // Reservation groups that the reservations are members of.
+	Reservations                Reservations                          `json:"Reservations"`      // The reservations that collide with the specified interval.
+	Services                    Services                              `json:"Services"`          // Services that have been reserved.
 	Resources                   resources.Resources                   // Assigned resources of the reservations.
 	ResourceCategories          resources.ResourceCategories          // Resource categories of the resources.
 	ResourceCategoryAssignments resources.ResourceCategoryAssignments // Assignments of the resources to categories.
-	Notes                       OrderNotes                              `json:"Notes"` // Notes of the reservations.
-	Cursor                      string                                  `json:"Cursor"`
+	Notes                       OrderNotes                            `json:"Notes"` // Notes of the reservations.
+	Cursor                      string                                `json:"Cursor"`
+}
+
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
 }
 
 type Reservations []Reservation
@@ -90,6 +103,10 @@ func (r AllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type ReservationExtent struct {
 	BusinessSegments            bool             `json:"BusinessSegments"`            // Whether the response should contain business segmentation.
 	Customers                   bool             `json:"Customers"`                   // Whether the response should contain customers of the reservations.
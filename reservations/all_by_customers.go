@@ -1,6 +1,10 @@
 package reservations
 
-import "github.com/omniboost/go-mews/json"
+import (
+	"context"
+
+	"github.com/omniboost/go-mews/json"
+)
 
 const (
 	endpointAllByCustomers = "reservations/getAllByCustomers"
@@ -8,18 +12,19 @@ const (
 
 // List all products
 func (s *APIService) AllByCustomers(requestBody *AllByCustomersRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllByCustomersContext(requestBody.GetContext(), requestBody)
+}
 
+// AllByCustomersContext is AllByCustomers with a context and per-call options.
+func (s *APIService) AllByCustomersContext(ctx context.Context, requestBody *AllByCustomersRequest, opts ...json.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAllByCustomers)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package reservations
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all products
 func (s *APIService) GetAll20230606(requestBody *GetAll20230606Request) (*AllResponse20230606, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.GetAll20230606Context(requestBody.GetContext(), requestBody)
+}
 
+// GetAll20230606Context is GetAll20230606 with a context and per-call options.
+func (s *APIService) GetAll20230606Context(ctx context.Context, requestBody *GetAll20230606Request, opts ...base.RequestOption) (*AllResponse20230606, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointGetAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse20230606{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,11 +37,21 @@ func (s *APIService) GetAll20230606(requestBody *GetAll20230606Request) (*AllRes
 	return responseBody, err
 }
 
+// GetAll20230606Iter iterates over all reservations, following the response cursor until
+// every page has been fetched.
+func (s *APIService) GetAll20230606Iter(requestBody *GetAll20230606Request, opts ...base.PageOption) iter.Seq2[Reservation20230606, error] {
+	return base.Items(requestBody, s.GetAll20230606, func(r *AllResponse20230606) []Reservation20230606 { return r.Reservations }, opts...)
+}
+
 type AllResponse20230606 struct {
 	Reservations Reservations20230606
 	Cursor       string `json:"Cursor"`
 }
 
+func (r *AllResponse20230606) GetCursor() string {
+	return r.Cursor
+}
+
 func (s *APIService) NewGetAll20230606Request() *GetAll20230606Request {
 	return &GetAll20230606Request{}
 }
@@ -49,7 +62,7 @@ type GetAll20230606Request struct {
 
 	EnterpriseIDs       []string                   `json:"EnterpriseIds,omitempty"`       // Unique identifiers of the Enterprises.
 	ReservationIDs      []string                   `json:"ReservationIds,omitempty"`      // Unique identifiers of the Reservations.
-	ServiceIDs          []string                   `json:"ServiceIds,omitempty"`                    // Unique identifiers of the Services. If not provided, all bookable services are used.
+	ServiceIDs          []string                   `json:"ServiceIds,omitempty"`          // Unique identifiers of the Services. If not provided, all bookable services are used.
 	AccountIDs          []string                   `json:"AccountIds,omitempty"`          // Unique identifiers of accounts (currently only Customers, in the future also Companies) the reservation is associated with.
 	ReservationGroupIDs []string                   `json:"ReservationGroupIds,omitempty"` // Unique identifiers of Reservation groups.
 	AssignedResourceIds []string                   `json:"AssignedResourceIds,omitempty"`
@@ -67,6 +80,10 @@ func (r GetAll20230606Request) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *GetAll20230606Request) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type Reservations20230606 []Reservation20230606
 
 type Reservation20230606 struct {
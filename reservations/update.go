@@ -1,6 +1,8 @@
 package reservations
 
 import (
+	"context"
+
 	"github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
 	"github.com/omniboost/go-mews/orderitems"
@@ -12,18 +14,19 @@ const (
 
 // Update customer
 func (s *APIService) Update(requestBody *UpdateRequest) (*UpdateResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.UpdateContext(requestBody.GetContext(), requestBody)
+}
 
+// UpdateContext is Update with a context and per-call options.
+func (s *APIService) UpdateContext(ctx context.Context, requestBody *UpdateRequest, opts ...json.RequestOption) (*UpdateResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointUpdate)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &UpdateResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -15,18 +17,34 @@ const (
 
 // List all products
 func (s *APIService) BlocksAll(requestBody *BlocksAllRequest) (*BlocksAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
+	return s.BlocksAllContext(requestBody.GetContext(), requestBody)
+}
+
+// BlocksAllContext is BlocksAll with a context and per-call options. A
+// CollidingUTC interval longer than base.MaxIntervalMonths is split into
+// windows, whose pages are all fetched and merged into one response without a
+// cursor.
+func (s *APIService) BlocksAllContext(ctx context.Context, requestBody *BlocksAllRequest, opts ...json.RequestOption) (*BlocksAllResponse, error) {
+	if windows := base.Windows(requestBody.CollidingUTC.StartUTC, requestBody.CollidingUTC.EndUTC, base.MaxIntervalMonths); len(windows) > 1 {
+		return base.FetchWindows(ctx, windows, func(ctx context.Context, w base.Window) (*BlocksAllResponse, error) {
+			window := *requestBody
+			window.CollidingUTC = configuration.TimeInterval{StartUTC: w.Start, EndUTC: w.End}
+			window.Limitation.Cursor = ""
+			window.SetContext(ctx)
+			return base.MergePages(&window, func(r *BlocksAllRequest) (*BlocksAllResponse, error) {
+				return s.BlocksAllContext(ctx, r, opts...)
+			})
+		}, opts...)
 	}
 
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointBlocksAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &BlocksAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +53,12 @@ func (s *APIService) BlocksAll(requestBody *BlocksAllRequest) (*BlocksAllRespons
 	return responseBody, err
 }
 
+// BlocksAllIter iterates over all resource blocks, following the response cursor until
+// every page has been fetched.
+func (s *APIService) BlocksAllIter(requestBody *BlocksAllRequest, opts ...base.PageOption) iter.Seq2[ResourceBlock, error] {
+	return base.Items(requestBody, s.BlocksAll, func(r *BlocksAllResponse) []ResourceBlock { return r.ResourceBlocks }, opts...)
+}
+
 func (s *APIService) NewBlocksAllRequest() *BlocksAllRequest {
 	return &BlocksAllRequest{}
 }
@@ -55,11 +79,19 @@ func (r BlocksAllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *BlocksAllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type BlocksAllResponse struct {
 	ResourceBlocks ResourceBlocks `json:"ResourceBlocks"`
 	Cursor         string         `json:"Cursor"`
 }
 
+func (r *BlocksAllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ResourceBlocks []ResourceBlock
 
 type ResourceBlock struct {
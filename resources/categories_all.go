@@ -1,6 +1,9 @@
 package resources
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -12,18 +15,19 @@ const (
 
 // List all products
 func (s *APIService) CategoriesAll(requestBody *CategoriesAllRequest) (*CategoriesAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.CategoriesAllContext(requestBody.GetContext(), requestBody)
+}
 
+// CategoriesAllContext is CategoriesAll with a context and per-call options.
+func (s *APIService) CategoriesAllContext(ctx context.Context, requestBody *CategoriesAllRequest, opts ...json.RequestOption) (*CategoriesAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointResourceCategoriesAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &CategoriesAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +36,12 @@ func (s *APIService) CategoriesAll(requestBody *CategoriesAllRequest) (*Categori
 	return responseBody, err
 }
 
+// CategoriesAllIter iterates over all resource categories, following the response cursor until
+// every page has been fetched.
+func (s *APIService) CategoriesAllIter(requestBody *CategoriesAllRequest, opts ...json.PageOption) iter.Seq2[ResourceCategory, error] {
+	return json.Items(requestBody, s.CategoriesAll, func(r *CategoriesAllResponse) []ResourceCategory { return r.ResourceCategories }, opts...)
+}
+
 func (s *APIService) NewCategoriesAllRequest() *CategoriesAllRequest {
 	return &CategoriesAllRequest{}
 }
@@ -43,12 +53,17 @@ type CategoriesAllRequest struct {
 	ServiceIDs          []string                   `json:"ServiceIds,omitempty"`          // Unique identifiers of Services to which the resource categories belong.
 	UpdatedUTC          configuration.TimeInterval `json:"UpdatedUtc,omitempty"`          // Interval in which the resource categories were updated.
 	ActivityStates      ActivityStates             `json:"ActivityStates,omitempty"`      // Whether to return only active, only deleted or both records.
+	Limitation          json.Limitation            `json:"Limitation,omitempty"`          // Limitation on the quantity of data returned.
 }
 
 func (r CategoriesAllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *CategoriesAllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type ActivityStates []ActivityState
 
 type ActivityState string
@@ -58,6 +73,10 @@ type CategoriesAllResponse struct {
 	Cursor             string             `json:"Cursor"` // Unique identifier of the last and hence oldest resource category returned. This can be used in Limitation in a subsequent request to fetch the next batch of older resource categories.
 }
 
+func (r *CategoriesAllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ResourceCategories []ResourceCategory
 
 type ResourceCategory struct {
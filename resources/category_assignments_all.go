@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all products
 func (s *APIService) CategoryAssignmentsAll(requestBody *CategoryAssignmentsAllRequest) (*CategoryAssignmentsAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.CategoryAssignmentsAllContext(requestBody.GetContext(), requestBody)
+}
 
+// CategoryAssignmentsAllContext is CategoryAssignmentsAll with a context and per-call options.
+func (s *APIService) CategoryAssignmentsAllContext(ctx context.Context, requestBody *CategoryAssignmentsAllRequest, opts ...base.RequestOption) (*CategoryAssignmentsAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointResourceCategoryAssignmentsAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &CategoryAssignmentsAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +37,14 @@ func (s *APIService) CategoryAssignmentsAll(requestBody *CategoryAssignmentsAllR
 	return responseBody, err
 }
 
+// CategoryAssignmentsAllIter iterates over all resource category assignments, following the response cursor until
+// every page has been fetched.
+func (s *APIService) CategoryAssignmentsAllIter(requestBody *CategoryAssignmentsAllRequest, opts ...base.PageOption) iter.Seq2[ResourceCategoryAssignment, error] {
+	return base.Items(requestBody, s.CategoryAssignmentsAll, func(r *CategoryAssignmentsAllResponse) []ResourceCategoryAssignment {
+		return r.ResourceCategoryAssignments
+	}, opts...)
+}
+
 func (s *APIService) NewCategoryAssignmentsAllRequest() *CategoryAssignmentsAllRequest {
 	return &CategoryAssignmentsAllRequest{}
 }
@@ -53,11 +64,19 @@ func (r CategoryAssignmentsAllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *CategoryAssignmentsAllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type CategoryAssignmentsAllResponse struct {
 	ResourceCategoryAssignments ResourceCategoryAssignments `json:"ResourceCategoryAssignments"` // Resource category assignments.
 	Cursor                      string                      `json:"Cursor"`                      // Unique identifier of the last and hence oldest resource category assignment returned. This can be used in Limitation in a subsequent request to fetch the next batch of older resource category assignments.
 }
 
+func (r *CategoryAssignmentsAllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ResourceCategoryAssignments []ResourceCategoryAssignment
 
 type ResourceCategoryAssignment struct {
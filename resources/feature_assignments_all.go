@@ -1,6 +1,8 @@
 package resources
 
 import (
+	"context"
+	"iter"
 	"time"
 
 	"github.com/omniboost/go-mews/configuration"
@@ -14,18 +16,19 @@ const (
 
 // List all products
 func (s *APIService) FeatureAssignmentsAll(requestBody *FeatureAssignmentsAllRequest) (*FeatureAssignmentsAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.FeatureAssignmentsAllContext(requestBody.GetContext(), requestBody)
+}
 
+// FeatureAssignmentsAllContext is FeatureAssignmentsAll with a context and per-call options.
+func (s *APIService) FeatureAssignmentsAllContext(ctx context.Context, requestBody *FeatureAssignmentsAllRequest, opts ...base.RequestOption) (*FeatureAssignmentsAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointResourceFeatureAssignmentsAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &FeatureAssignmentsAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -34,6 +37,14 @@ func (s *APIService) FeatureAssignmentsAll(requestBody *FeatureAssignmentsAllReq
 	return responseBody, err
 }
 
+// FeatureAssignmentsAllIter iterates over all resource feature assignments, following the response cursor until
+// every page has been fetched.
+func (s *APIService) FeatureAssignmentsAllIter(requestBody *FeatureAssignmentsAllRequest, opts ...base.PageOption) iter.Seq2[ResourceFeatureAssignment, error] {
+	return base.Items(requestBody, s.FeatureAssignmentsAll, func(r *FeatureAssignmentsAllResponse) []ResourceFeatureAssignment {
+		return r.ResourceFeatureAssignments
+	}, opts...)
+}
+
 func (s *APIService) NewFeatureAssignmentsAllRequest() *FeatureAssignmentsAllRequest {
 	return &FeatureAssignmentsAllRequest{}
 }
@@ -53,11 +64,19 @@ func (r FeatureAssignmentsAllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *FeatureAssignmentsAllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
+
 type FeatureAssignmentsAllResponse struct {
 	ResourceFeatureAssignments ResourceFeatureAssignments `json:"ResourceFeatureAssignments"` // Resource features assignments.
 	Cursor                     string                     `json:"Cursor"`                     // Unique identifier of the last and hence oldest resource feature assignment returned. This can be used in Limitation in a subsequent request to fetch the next batch of older resource feature assignments.
 }
 
+func (r *FeatureAssignmentsAllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ResourceFeatureAssignments []ResourceFeatureAssignment
 
 type ResourceFeatureAssignment struct {
@@ -1,6 +1,9 @@
 package resources
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	"github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/omitempty"
@@ -12,18 +15,19 @@ const (
 
 // List all products
 func (s *APIService) FeaturesAll(requestBody *FeaturesAllRequest) (*FeaturesAllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.FeaturesAllContext(requestBody.GetContext(), requestBody)
+}
 
+// FeaturesAllContext is FeaturesAll with a context and per-call options.
+func (s *APIService) FeaturesAllContext(ctx context.Context, requestBody *FeaturesAllRequest, opts ...json.RequestOption) (*FeaturesAllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointResourceFeaturesAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &FeaturesAllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +36,12 @@ func (s *APIService) FeaturesAll(requestBody *FeaturesAllRequest) (*FeaturesAllR
 	return responseBody, err
 }
 
+// FeaturesAllIter iterates over all resource features, following the response cursor until
+// every page has been fetched.
+func (s *APIService) FeaturesAllIter(requestBody *FeaturesAllRequest, opts ...json.PageOption) iter.Seq2[ResourceFeature, error] {
+	return json.Items(requestBody, s.FeaturesAll, func(r *FeaturesAllResponse) []ResourceFeature { return r.ResourceFeatures }, opts...)
+}
+
 func (s *APIService) NewFeaturesAllRequest() *FeaturesAllRequest {
 	return &FeaturesAllRequest{}
 }
@@ -43,17 +53,26 @@ type FeaturesAllRequest struct {
 	ResourceFeatureIDs []string                   `json:"ResourceFeatureIds,omitempty"` // Unique identifiers of Resource features.
 	UpdatedUTC         configuration.TimeInterval `json:"UpdatedUtc,omitempty"`         // Interval in which the resource features were updated.
 	ActivityStates     ActivityStates             `json:"ActivityStates,omitempty"`     // Whether to return only active, only deleted or both records.
+	Limitation         json.Limitation            `json:"Limitation,omitempty"`         // Limitation on the quantity of data returned.
 }
 
 func (r FeaturesAllRequest) MarshalJSON() ([]byte, error) {
 	return omitempty.MarshalJSON(r)
 }
 
+func (r *FeaturesAllRequest) GetLimitation() *json.Limitation {
+	return &r.Limitation
+}
+
 type FeaturesAllResponse struct {
 	ResourceFeatures ResourceFeatures `json:"ResourceFeatures"`
 	Cursor           string           `json:"Cursor"` // Unique identifier of the last and hence oldest resource feature returned. This can be used in Limitation in a subsequent request to fetch the next batch of older resource features.
 }
 
+func (r *FeaturesAllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ResourceFeatures []ResourceFeature
 
 type ResourceFeature struct {
@@ -1,6 +1,9 @@
 package serviceordernotes
 
 import (
+	"context"
+	"iter"
+
 	"github.com/omniboost/go-mews/configuration"
 	base "github.com/omniboost/go-mews/json"
 )
@@ -15,18 +18,19 @@ const (
 
 // List all products
 func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AllContext(requestBody.GetContext(), requestBody)
+}
 
+// AllContext is All with a context and per-call options.
+func (s *APIService) AllContext(ctx context.Context, requestBody *AllRequest, opts ...base.RequestOption) (*AllResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAll)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AllResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -35,11 +39,21 @@ func (s *APIService) All(requestBody *AllRequest) (*AllResponse, error) {
 	return responseBody, err
 }
 
+// AllIter iterates over all service order notes, following the response cursor until
+// every page has been fetched.
+func (s *APIService) AllIter(requestBody *AllRequest, opts ...base.PageOption) iter.Seq2[ServiceOrderNote, error] {
+	return base.Items(requestBody, s.All, func(r *AllResponse) []ServiceOrderNote { return r.ServiceOrderNotes }, opts...)
+}
+
 type AllResponse struct {
 	ServiceOrderNotes ServiceOrderNotes `json:"ServiceOrderNotes"` // Services offered by the enterprise.
 	Cursor            string            `json:"Cursor"`
 }
 
+func (r *AllResponse) GetCursor() string {
+	return r.Cursor
+}
+
 type ServiceOrderNotes []ServiceOrderNote
 
 type ServiceOrderNote struct {
@@ -65,3 +79,7 @@ type AllRequest struct {
 
 	Limitation base.Limitation `json:"Limitation,omitempty"`
 }
+
+func (r *AllRequest) GetLimitation() *base.Limitation {
+	return &r.Limitation
+}
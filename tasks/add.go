@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"context"
 	"time"
 
 	"github.com/omniboost/go-mews/json"
@@ -12,18 +13,19 @@ const (
 
 // List all products
 func (s *Service) Add(requestBody *AddRequest) (*AddResponse, error) {
-	// @TODO: create wrapper?
-	if err := s.Client.CheckTokens(); err != nil {
-		return nil, err
-	}
+	return s.AddContext(requestBody.GetContext(), requestBody)
+}
 
+// AddContext is Add with a context and per-call options.
+func (s *Service) AddContext(ctx context.Context, requestBody *AddRequest, opts ...json.RequestOption) (*AddResponse, error) {
+	// @TODO: create wrapper?
 	apiURL, err := s.Client.GetApiURL(endpointAdd)
 	if err != nil {
 		return nil, err
 	}
 
 	responseBody := &AddResponse{}
-	httpReq, err := s.Client.NewRequest(apiURL, requestBody)
+	httpReq, err := s.Client.NewRequestWithContext(ctx, apiURL, requestBody, opts...)
 	if err != nil {
 		return nil, err
 	}
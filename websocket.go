@@ -3,8 +3,9 @@ package mews
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"log"
+	"log/slog"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httputil"
@@ -13,6 +14,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/omniboost/go-mews/commands"
+	base "github.com/omniboost/go-mews/json"
 	"github.com/omniboost/go-mews/reservations"
 	"github.com/omniboost/go-mews/resources"
 )
@@ -28,6 +30,8 @@ var (
 		Host:   "ws.mews-demo.com",
 		Path:   "/ws/connector",
 	}
+
+	ErrNoWebsocketURL = errors.New("the environment has no websocket endpoint")
 )
 
 const (
@@ -54,11 +58,16 @@ type Websocket struct {
 	// Debugging flag
 	debug bool
 
+	// Optional structured logger
+	logger *slog.Logger
+
 	// Disallow unknown json fields
 	disallowUnknownFields bool
 
 	accessToken string
 	clientToken string
+	// Resolves the tokens of every connection, overrides the tokens above
+	tokenProvider base.TokenProvider
 
 	connection *websocket.Conn
 	cancelFunc context.CancelFunc
@@ -94,6 +103,12 @@ func (ws *Websocket) SetAccessToken(accessToken string) {
 	ws.accessToken = accessToken
 }
 
+// SetTokenProvider resolves the tokens with provider every time the websocket
+// connects, instead of using the access and client token.
+func (ws *Websocket) SetTokenProvider(provider base.TokenProvider) {
+	ws.tokenProvider = provider
+}
+
 func (ws Websocket) ClientToken() string {
 	return ws.clientToken
 }
@@ -106,9 +121,12 @@ func (ws *Websocket) BaseURL() *url.URL {
 	return ws.baseURL
 }
 
+// SetBaseURL sets the endpoint of the websocket, nil leaves it without one.
 func (ws *Websocket) SetBaseURL(baseURL *url.URL) {
 	ws.baseURL = baseURL
-	ws.baseURL.Scheme = "wss"
+	if ws.baseURL != nil {
+		ws.baseURL.Scheme = "wss"
+	}
 }
 
 func (ws *Websocket) Debug() bool {
@@ -119,6 +137,31 @@ func (ws *Websocket) SetDebug(debug bool) {
 	ws.debug = debug
 }
 
+func (ws *Websocket) Logger() *slog.Logger {
+	return ws.logger
+}
+
+func (ws *Websocket) SetLogger(logger *slog.Logger) {
+	ws.logger = logger
+}
+
+func (ws *Websocket) logging() bool {
+	return ws.logger != nil || ws.debug
+}
+
+// logDebug logs to the structured logger when it is set, or to the standard
+// logger in debug mode. args are key value pairs as with slog.
+func (ws *Websocket) logDebug(msg string, args ...any) {
+	if ws.logger != nil {
+		ws.logger.Debug(msg, args...)
+		return
+	}
+
+	if ws.debug {
+		log.Println(append([]any{msg}, args...)...)
+	}
+}
+
 func (ws *Websocket) CommandEvents() chan (CommandEvent) {
 	ws.cmdChan = make(chan CommandEvent)
 	return ws.cmdChan
@@ -148,6 +191,10 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 	var err error
 	var resp *http.Response
 
+	if ws.BaseURL() == nil {
+		return ErrNoWebsocketURL
+	}
+
 	d := &websocket.Dialer{
 		Proxy:            http.ProxyFromEnvironment,
 		HandshakeTimeout: 45 * time.Second,
@@ -158,9 +205,17 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 		return err
 	}
 
+	tokens := base.Tokens{AccessToken: ws.AccessToken(), ClientToken: ws.ClientToken()}
+	if ws.tokenProvider != nil {
+		tokens, err = base.ResolveTokens(ctx, ws.tokenProvider)
+		if err != nil {
+			return err
+		}
+	}
+
 	cookies := []*http.Cookie{
-		&http.Cookie{Name: "ClientToken", Value: ws.ClientToken()},
-		&http.Cookie{Name: "AccessToken", Value: ws.AccessToken()},
+		&http.Cookie{Name: "ClientToken", Value: tokens.ClientToken},
+		&http.Cookie{Name: "AccessToken", Value: tokens.AccessToken},
 	}
 
 	u := *ws.BaseURL()
@@ -169,9 +224,12 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 
 	ws.connection, resp, err = d.Dial(ws.BaseURL().String(), nil)
 	if err != nil {
-		if ws.debug && resp != nil {
-			b, _ := httputil.DumpResponse(resp, true)
-			log.Println(string(b))
+		if resp != nil && ws.logging() {
+			// the handshake response may set cookies
+			r := *resp
+			r.Header = base.RedactHeader(resp.Header)
+			b, _ := httputil.DumpResponse(&r, true)
+			ws.logDebug("websocket: handshake failed", "status", resp.StatusCode, "response", string(b))
 		}
 		return err
 	}
@@ -180,17 +238,13 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 	ws.connection.SetReadDeadline(time.Now().Add(pongWait))
 	// After receiving a pong: reset the read deadline
 	ws.connection.SetPongHandler(func(data string) error {
-		if ws.Debug() {
-			log.Printf("Received pong message; %s", data)
-		}
+		ws.logDebug("websocket: received pong message", "data", data)
 		ws.connection.SetReadDeadline(time.Now().Add(pongWait))
 		return nil
 	})
 
 	ws.connection.SetPingHandler(func(data string) error {
-		if ws.Debug() {
-			log.Printf("Received ping message; %s", data)
-		}
+		ws.logDebug("websocket: received ping message", "data", data)
 		return nil
 	})
 
@@ -213,17 +267,13 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 		for {
 			select {
 			case <-ctx.Done():
-				if ws.debug {
-					log.Println("stopping reading messages: context is canceled")
-				}
+				ws.logDebug("websocket: stopping reading messages: context is canceled")
 				break
 			default:
-				if ws.debug {
-					log.Println("waiting to receive message")
-				}
+				ws.logDebug("websocket: waiting to receive message")
 				msgType, msg, err := ws.connection.ReadMessage()
-				if ws.debug {
-					log.Printf("received msg on websocket: %s (%d)", msg, msgType)
+				if ws.logging() {
+					ws.logDebug("websocket: received message", "type", msgType, "bytes", len(msg), "message", string(base.Redact(msg)))
 				}
 				if err != nil {
 					if ws.errChan != nil {
@@ -260,13 +310,9 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 							}
 							return
 						}
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushing command %s to cmd channel", cmdEvent.ID))
-						}
+						ws.logDebug("websocket: pushing event to channel", "type", event.Type, "id", cmdEvent.ID)
 						ws.cmdChan <- cmdEvent
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushed command %s to cmd channel", cmdEvent.ID))
-						}
+						ws.logDebug("websocket: pushed event to channel", "type", event.Type, "id", cmdEvent.ID)
 					}
 
 					if event.Type == EventTypeReservation && ws.resChan != nil {
@@ -278,13 +324,9 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 							}
 							return
 						}
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushing command %s to res channel", resEvent.ID))
-						}
+						ws.logDebug("websocket: pushing event to channel", "type", event.Type, "id", resEvent.ID)
 						ws.resChan <- resEvent
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushed command %s to res channel", resEvent.ID))
-						}
+						ws.logDebug("websocket: pushed event to channel", "type", event.Type, "id", resEvent.ID)
 					} else if event.Type == EventTypeResource && ws.resourceChan != nil {
 						resourceEvent := ResourceEvent{}
 						err := json.Unmarshal(b, &resourceEvent)
@@ -294,13 +336,9 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 							}
 							return
 						}
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushing command %s to resource channel", resourceEvent.ID))
-						}
+						ws.logDebug("websocket: pushing event to channel", "type", event.Type, "id", resourceEvent.ID)
 						ws.resourceChan <- resourceEvent
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushed command %s to resource channel", resourceEvent.ID))
-						}
+						ws.logDebug("websocket: pushed event to channel", "type", event.Type, "id", resourceEvent.ID)
 					} else if event.Type == EventTypePriceUpdate && ws.priceUpdateChan != nil {
 						priceUpdateEvent := PriceUpdateEvent{}
 						err := json.Unmarshal(b, &priceUpdateEvent)
@@ -310,13 +348,9 @@ func (ws *Websocket) Connect(ctx context.Context) error {
 							}
 							return
 						}
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushing command %s to price update channel", priceUpdateEvent.ID))
-						}
+						ws.logDebug("websocket: pushing event to channel", "type", event.Type, "id", priceUpdateEvent.ID)
 						ws.priceUpdateChan <- priceUpdateEvent
-						if ws.debug {
-							log.Println(fmt.Sprintf("websocket: pushed command %s to prive update channel", priceUpdateEvent.ID))
-						}
+						ws.logDebug("websocket: pushed event to channel", "type", event.Type, "id", priceUpdateEvent.ID)
 					}
 				}
 			}
@@ -333,17 +367,13 @@ func (ws *Websocket) KeepAlive(ctx context.Context) error {
 	for {
 		select {
 		case <-ticker.C:
-			if ws.Debug() {
-				log.Println("sending keep alive ping message")
-			}
+			ws.logDebug("websocket: sending keep alive ping message")
 			err := ws.connection.WriteControl(websocket.PingMessage, []byte{}, time.Now().Add(writeWait))
 			if err != nil {
 				return err
 			}
 		case <-ctx.Done():
-			if ws.Debug() {
-				log.Println("keep alive stopped")
-			}
+			ws.logDebug("websocket: keep alive stopped")
 			return nil
 		}
 	}
@@ -351,9 +381,7 @@ func (ws *Websocket) KeepAlive(ctx context.Context) error {
 
 func (ws *Websocket) Close() error {
 	// Send close message to the peer
-	if ws.Debug() {
-		log.Println("send close message to peer")
-	}
+	ws.logDebug("websocket: send close message to peer")
 	message := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
 	err := ws.connection.WriteControl(websocket.CloseMessage, message, time.Now().Add(writeWait))
 	if err != nil {